@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sachinDcoder/mcp_azure_nexus_go/tools"
+)
+
+// ServerConfig is the optional JSON configuration file passed with -config.
+type ServerConfig struct {
+	Tools   ToolsConfig   `json:"tools"`
+	Audit   AuditConfig   `json:"audit"`
+	Logging LoggingConfig `json:"logging"`
+	Tracing TracingConfig `json:"tracing"`
+	HTTP    HTTPConfig    `json:"http"`
+	// Context holds the server wide defaults of the session context. The AZURE_SUBSCRIPTION_ID,
+	// NEXUS_RESOURCE_GROUP, NEXUS_LOCATION and NEXUS_FABRIC environment variables override them.
+	Context tools.SessionContext `json:"context"`
+	// Watch sets how often resources that clients subscribed to are polled for changes.
+	Watch tools.WatcherConfig `json:"watch"`
+}
+
+// ToolsConfig selects which tools the server registers. Allow and Deny entries are either a tool
+// category such as "l3isolationdomain" or a glob pattern over tool names such as "get_*".
+type ToolsConfig struct {
+	// ReadOnly registers only the tools that are annotated as read-only (get, list and status tools).
+	ReadOnly bool `json:"readOnly"`
+	// Allow, when not empty, registers only the tools matching at least one entry.
+	Allow []string `json:"allow"`
+	// Deny never registers the tools matching any entry, even if they are allowed.
+	Deny []string `json:"deny"`
+	// SkipConfirmation runs destructive tools without asking the user to confirm them first.
+	SkipConfirmation bool `json:"skipConfirmation"`
+}
+
+// AuditConfig selects where every tool call is recorded.
+type AuditConfig struct {
+	// File is the JSONL file that audit entries are appended to.
+	File string `json:"file"`
+	// Syslog also sends every audit entry to the local syslog daemon.
+	Syslog bool `json:"syslog"`
+}
+
+func (config AuditConfig) enabled() bool {
+	return config.File != "" || config.Syslog
+}
+
+func loadServerConfig(configPath string) (ServerConfig, error) {
+	var config ServerConfig
+	if configPath == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return config, fmt.Errorf("failed to read config %s: %v", configPath, err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to unmarshal config %s: %v", configPath, err)
+	}
+
+	if err := config.HTTP.validate(); err != nil {
+		return config, fmt.Errorf("invalid config %s: %v", configPath, err)
+	}
+
+	for _, pattern := range append(config.Tools.Allow, config.Tools.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return config, fmt.Errorf("invalid tool pattern '%s' in config %s: %v", pattern, configPath, err)
+		}
+	}
+
+	return config, nil
+}
+
+func (config ToolsConfig) enabled(category string, tool mcp.Tool) bool {
+	if config.ReadOnly && (tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint) {
+		return false
+	}
+
+	if len(config.Allow) > 0 && !matchesTool(config.Allow, category, tool.Name) {
+		return false
+	}
+
+	return !matchesTool(config.Deny, category, tool.Name)
+}
+
+func matchesTool(entries []string, category, toolName string) bool {
+	for _, entry := range entries {
+		if entry == category {
+			return true
+		}
+		if matched, _ := path.Match(entry, toolName); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sachinDcoder/mcp_azure_nexus_go/tools"
+)
+
+// HTTPConfig secures the streamable HTTP transport.
+type HTTPConfig struct {
+	// Tokens maps the name of every caller to the bearer token it authenticates with. Without
+	// tokens the server only listens on the loopback interface.
+	Tokens map[string]string `json:"tokens"`
+}
+
+type httpCallerKey struct{}
+
+func (config HTTPConfig) validate() error {
+	callers := map[string]string{}
+	for caller, token := range config.Tokens {
+		if caller == "" {
+			return fmt.Errorf("http token with an empty caller name")
+		}
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("http token of caller '%s' is empty", caller)
+		}
+		if other, ok := callers[token]; ok {
+			return fmt.Errorf("callers '%s' and '%s' share the same http token", other, caller)
+		}
+		callers[token] = caller
+	}
+	return nil
+}
+
+// listenAddress returns the address to serve HTTP on. Without authentication an address without a
+// host such as :8080 is bound to 127.0.0.1, and any other host than a loopback one is refused, as
+// anyone reaching the port could run every tool with the credential of the server.
+func (config HTTPConfig) listenAddress(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid http address '%s': %v", address, err)
+	}
+	if len(config.Tokens) > 0 {
+		return address, nil
+	}
+
+	if host == "" {
+		return net.JoinHostPort("127.0.0.1", port), nil
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return "", fmt.Errorf("refusing to serve MCP over HTTP on %s without authentication, configure http.tokens or listen on 127.0.0.1", address)
+	}
+	return address, nil
+}
+
+// authenticate rejects requests without a valid bearer token, when tokens are configured, and
+// attaches the name of the authenticated caller to the request context.
+func (config HTTPConfig) authenticate(next http.Handler) http.Handler {
+	if len(config.Tokens) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		caller := ""
+		if ok {
+			for name, expected := range config.Tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+					caller = name
+				}
+			}
+		}
+		if caller == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+SERVICE_NAME+`"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), httpCallerKey{}, caller)))
+	})
+}
+
+// httpCallerContext passes the authenticated caller of an HTTP request on to the tool calls it
+// makes, for the audit log.
+func httpCallerContext(ctx context.Context, r *http.Request) context.Context {
+	if caller, ok := r.Context().Value(httpCallerKey{}).(string); ok {
+		return tools.WithCaller(ctx, caller)
+	}
+	return ctx
+}
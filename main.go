@@ -1,85 +1,386 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/user"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sachinDcoder/mcp_azure_nexus_go/tools"
 )
 
+const (
+	MCP_ENDPOINT_PATH     = "/mcp"
+	METRICS_ENDPOINT_PATH = "/metrics"
+)
+
 func main() {
-	fmt.Println("Welcome to Azure Nexus MCP server!")
+	recordCassette := flag.String("record-cassette", "", "Record every ARM exchange made by the tools into this cassette file.")
+	replayCassette := flag.String("replay-cassette", "", "Serve ARM responses from this cassette file instead of calling Azure.")
+	readOnly := flag.Bool("read-only", false, "Register only the read-only tools (get, list and status).")
+	skipConfirmation := flag.Bool("skip-confirmation", false, "Run destructive tools without asking the user to confirm them.")
+	protectionPolicy := flag.String("protection-policy", "", "Path to the JSON policy of resources that the tools must never change.")
+	auditLog := flag.String("audit-log", "", "Append a JSON line for every tool call to this audit file.")
+	logLevel := flag.String("log-level", "", "Log level: debug, info, warn or error.")
+	logFile := flag.String("log-file", "", "Write logs to this file instead of stderr.")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "Export OpenTelemetry spans to this OTLP/HTTP collector, e.g. http://localhost:4318.")
+	httpAddress := flag.String("http", "", "Serve MCP over streamable HTTP on this address, e.g. :8080, with Prometheus metrics at /metrics. Listens on 127.0.0.1 only unless http tokens are configured. Uses stdio when not set.")
+	traceFile := flag.String("trace-file", "", "Export OpenTelemetry spans as JSON to this file.")
+	watchInterval := flag.String("watch-interval", "", "Poll subscribed resources at this interval, e.g. 30s.")
+	configPath := flag.String("config", "", "Path to the JSON server configuration file.")
+	flag.Parse()
+
+	config, err := loadServerConfig(*configPath)
+	if err != nil {
+		exitOnStartupError(err)
+	}
+	if *logLevel != "" {
+		config.Logging.Level = *logLevel
+	}
+	if *logFile != "" {
+		config.Logging.File = *logFile
+	}
+	if *otlpEndpoint != "" {
+		config.Tracing.OTLPEndpoint = *otlpEndpoint
+	}
+	if *traceFile != "" {
+		config.Tracing.File = *traceFile
+	}
+	if *readOnly {
+		config.Tools.ReadOnly = true
+	}
+	if *skipConfirmation {
+		config.Tools.SkipConfirmation = true
+	}
+	if *auditLog != "" {
+		config.Audit.File = *auditLog
+	}
+	if *watchInterval != "" {
+		config.Watch.Interval = *watchInterval
+	}
+
+	closeLog, err := setupLogging(config.Logging)
+	if err != nil {
+		exitOnStartupError(err)
+	}
+	defer closeLog()
+
+	shutdownTracing, err := setupTracing(context.Background(), config.Tracing)
+	if err != nil {
+		exitOnStartupError(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	slog.Info("Welcome to Azure Nexus MCP server!")
+
+	clientRetriever, err := newClientRetriever(*recordCassette, *replayCassette)
+	if err != nil {
+		exitOnStartupError(err)
+	}
+	clientRetriever.SkipConfirmation = config.Tools.SkipConfirmation
+	clientRetriever.Context = tools.NewContextStore(config.Context)
+	// first in the pipeline, so ARM requests served from a cassette are traced as well
+	clientRetriever.PerRetryPolicies = append([]policy.Policy{tools.TelemetryPolicy{}}, clientRetriever.PerRetryPolicies...)
+
+	if *protectionPolicy != "" {
+		clientRetriever.Protection, err = tools.LoadProtectionPolicy(*protectionPolicy)
+		if err != nil {
+			exitOnStartupError(err)
+		}
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		clientRetriever.Context.Forget(session)
+	})
+
+	completer := tools.NewCompleter(clientRetriever)
+
+	serverOptions := []server.ServerOption{
+		server.WithLogging(),
+		server.WithElicitation(),
+		server.WithResourceCapabilities(true, false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completer),
+		server.WithResourceCompletionProvider(completer),
+		server.WithHooks(hooks),
+		// outermost, so the logs, the audit log and the tools all see the defaulted arguments
+		server.WithToolHandlerMiddleware(clientRetriever.Context.Middleware()),
+		server.WithToolHandlerMiddleware(tools.TelemetryMiddleware()),
+	}
+
+	if config.Audit.enabled() {
+		auditLogger, err := tools.NewAuditLogger(config.Audit.File, config.Audit.Syslog)
+		if err != nil {
+			exitOnStartupError(err)
+		}
+		defer auditLogger.Close()
+
+		// first in the pipeline, so it also sees responses served from a cassette
+		clientRetriever.PerRetryPolicies = append([]policy.Policy{tools.CorrelationPolicy{}}, clientRetriever.PerRetryPolicies...)
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(auditLogger.Middleware()))
+	}
+
+	var metricsRegistry *prometheus.Registry
+	if *httpAddress != "" {
+		metricsRegistry = prometheus.NewRegistry()
+		metricsRegistry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+		metrics := tools.NewMetrics(metricsRegistry)
+		clientRetriever.PerRetryPolicies = append([]policy.Policy{metrics.Policy()}, clientRetriever.PerRetryPolicies...)
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(metrics.Middleware()))
+	}
+
+	// created last, so its polls go through the same pipeline as the tools
+	watcher, err := tools.NewWatcher(clientRetriever, config.Watch)
+	if err != nil {
+		exitOnStartupError(err)
+	}
+	defer watcher.Close()
+
+	hooks.AddAfterSubscribe(func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+		watcher.Subscribe(ctx, message.Params.URI)
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+		watcher.Unsubscribe(ctx, message.Params.URI)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		watcher.Forget(session)
+	})
 
 	// Create MCP server
 	s := server.NewMCPServer(
 		"Azure Nexus MCP server 🚀",
 		"0.0.1",
-		server.WithLogging(),
+		serverOptions...,
+	)
+	watcher.SetServer(s)
+
+	slog.Info("Registering tools...")
+
+	registry := toolRegistry{
+		server:          s,
+		clientRetriever: clientRetriever,
+		config:          config.Tools,
+	}
+
+	registry.add(tools.SUBSCRIPTION_CATEGORY,
+		tools.ListSubscriptions,
+		tools.FindNexusResources,
+	)
+
+	registry.add(tools.RESOURCE_GRAPH_CATEGORY,
+		tools.QueryResourceGraph,
+	)
+
+	registry.add(tools.RESOURCE_GROUP_CATEGORY,
+		tools.CreateResourceGroup,
+		tools.DeleteResourceGroup,
+		tools.GetResourceGroup,
+		tools.ListResourcesInRG,
+		tools.ListResourceGroups,
+	)
+
+	registry.add(tools.IP_PREFIX_CATEGORY,
+		tools.CreateIPPrefix,
+		tools.DeleteIPPrefix,
+		tools.PatchIPPrefix,
+		tools.GetIPPrefix,
 	)
 
-	fmt.Println("Registering tools...")
-
-	s.AddTool(tools.CreateResourceGroup(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteResourceGroup(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetResourceGroup(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.ListResourcesInRG(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateIPPrefix(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteIPPrefix(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchIPPrefix(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetIPPrefix(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateIPCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteIPCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchIPCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetIPCommunity(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateIPExtCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteIPExtCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchIPExtCommunity(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetIPExtCommunity(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateRoutePolicy(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteRoutePolicy(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchRoutePolicy(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetRoutePolicy(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateL2IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.EnableL2IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DisableL2IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL2IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL2IsolationDomainAdministrativeState(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL2IsolationDomainConfigurationState(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteL2IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchL2IsolationDomain(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateL3IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.EnableL3IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DisableL3IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL3IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL3IsolationDomainAdministrativeState(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetL3IsolationDomainConfigurationState(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.DeleteL3IsolationDomain(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchL3IsolationDomain(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateInternalNetwork(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchInternalNetwork(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetInternalNetwork(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CreateExternalNetwork(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.PatchExternalNetwork(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetExternalNetwork(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.CommitNetworkFabric(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetNetworkFabric(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.ListDevicesNetworkFabric(tools.ServiceClientRetriever{}))
-
-	s.AddTool(tools.GetNetworkDevice(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.RebootNetworkDevice(tools.ServiceClientRetriever{}))
-	s.AddTool(tools.GetLabStatus(tools.ServiceClientRetriever{}))
+	registry.add(tools.IP_COMMUNITY_CATEGORY,
+		tools.CreateIPCommunity,
+		tools.DeleteIPCommunity,
+		tools.PatchIPCommunity,
+		tools.GetIPCommunity,
+	)
+
+	registry.add(tools.IP_EXT_COMMUNITY_CATEGORY,
+		tools.CreateIPExtCommunity,
+		tools.DeleteIPExtCommunity,
+		tools.PatchIPExtCommunity,
+		tools.GetIPExtCommunity,
+	)
+
+	registry.add(tools.ROUTE_POLICY_CATEGORY,
+		tools.CreateRoutePolicy,
+		tools.DeleteRoutePolicy,
+		tools.PatchRoutePolicy,
+		tools.GetRoutePolicy,
+		tools.AnalyzeRoutePolicy,
+		tools.SimulateRoutePolicy,
+		tools.BuildRoutePolicy,
+	)
+
+	registry.add(tools.L2_ISOLATION_DOMAIN_CATEGORY,
+		tools.CreateL2IsolationDomain,
+		tools.EnableL2IsolationDomain,
+		tools.DisableL2IsolationDomain,
+		tools.GetL2IsolationDomain,
+		tools.GetL2IsolationDomainAdministrativeState,
+		tools.GetL2IsolationDomainConfigurationState,
+		tools.DeleteL2IsolationDomain,
+		tools.PatchL2IsolationDomain,
+	)
+
+	registry.add(tools.L3_ISOLATION_DOMAIN_CATEGORY,
+		tools.CreateL3IsolationDomain,
+		tools.EnableL3IsolationDomain,
+		tools.DisableL3IsolationDomain,
+		tools.GetL3IsolationDomain,
+		tools.GetL3IsolationDomainAdministrativeState,
+		tools.GetL3IsolationDomainConfigurationState,
+		tools.DeleteL3IsolationDomain,
+		tools.PatchL3IsolationDomain,
+	)
+
+	registry.add(tools.INTERNAL_NETWORK_CATEGORY,
+		tools.CreateInternalNetwork,
+		tools.PatchInternalNetwork,
+		tools.GetInternalNetwork,
+	)
+
+	registry.add(tools.EXTERNAL_NETWORK_CATEGORY,
+		tools.CreateExternalNetwork,
+		tools.PatchExternalNetwork,
+		tools.GetExternalNetwork,
+	)
+
+	registry.add(tools.NETWORK_FABRIC_CATEGORY,
+		tools.CommitNetworkFabric,
+		tools.GetNetworkFabric,
+		tools.ListDevicesNetworkFabric,
+	)
+
+	registry.add(tools.NETWORK_DEVICE_CATEGORY,
+		tools.GetNetworkDevice,
+		tools.RebootNetworkDevice,
+	)
+
+	registry.add(tools.LAB_CATEGORY,
+		tools.GetLabStatus,
+	)
+
+	registry.add(tools.CONTEXT_CATEGORY,
+		tools.SetContext,
+	)
+
+	s.AddResource(tools.ContextResource(clientRetriever))
+	s.AddResourceTemplates(tools.ResourceTemplates(clientRetriever)...)
+
+	slog.Info("Registered tools", "count", registry.count)
+
+	// a runbook is only offered when every tool it calls is available, e.g. not in read-only mode
+	prompts := 0
+	for _, runbook := range tools.Runbooks() {
+		if !registry.registered(runbook.Tools...) {
+			slog.Debug("Skipping prompt", "prompt", runbook.Prompt.Name)
+			continue
+		}
+		s.AddPrompts(runbook.ServerPrompt)
+		prompts++
+	}
+	slog.Info("Registered prompts", "count", prompts)
+
+	if *httpAddress != "" {
+		address, err := config.HTTP.listenAddress(*httpAddress)
+		if err != nil {
+			exitOnStartupError(err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle(MCP_ENDPOINT_PATH, server.NewStreamableHTTPServer(s,
+			server.WithEndpointPath(MCP_ENDPOINT_PATH),
+			server.WithHTTPContextFunc(httpCallerContext),
+		))
+		mux.Handle(METRICS_ENDPOINT_PATH, promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+
+		slog.Info("Serving MCP over HTTP", "address", address, "mcp", MCP_ENDPOINT_PATH, "metrics", METRICS_ENDPOINT_PATH, "authenticated", len(config.HTTP.Tokens) > 0)
+		if err := http.ListenAndServe(address, config.HTTP.authenticate(mux)); err != nil {
+			slog.Error("Server error", "error", err)
+		}
+		return
+	}
 
 	// Start the stdio server
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Printf("Server error: %v\n", err)
+	if err := server.ServeStdio(s, server.WithStdioContextFunc(stdioCallerContext)); err != nil {
+		slog.Error("Server error", "error", err)
+	}
+}
+
+func exitOnStartupError(err error) {
+	slog.Error("Startup error", "error", err)
+	os.Exit(1)
+}
+
+// stdioCallerContext records the local user running the server as the caller of the tool calls
+// made over stdio, as the MCP client that starts the server runs as that user.
+func stdioCallerContext(ctx context.Context) context.Context {
+	if current, err := user.Current(); err == nil {
+		return tools.WithCaller(ctx, current.Username)
+	}
+	return ctx
+}
+
+func newClientRetriever(recordCassette, replayCassette string) (tools.ServiceClientRetriever, error) {
+	clientRetriever := tools.ServiceClientRetriever{}
+
+	switch {
+	case recordCassette != "" && replayCassette != "":
+		return clientRetriever, fmt.Errorf("-record-cassette and -replay-cassette cannot be used together")
+	case recordCassette != "":
+		recorder, err := tools.NewCassetteRecorder(recordCassette)
+		if err != nil {
+			return clientRetriever, err
+		}
+		slog.Info("Recording ARM exchanges", "cassette", recordCassette)
+		clientRetriever.PerRetryPolicies = append(clientRetriever.PerRetryPolicies, recorder)
+	case replayCassette != "":
+		player, err := tools.LoadCassettePlayer(replayCassette)
+		if err != nil {
+			return clientRetriever, err
+		}
+		slog.Info("Replaying ARM exchanges", "cassette", replayCassette)
+		clientRetriever.Credential = tools.ReplayCredential{}
+		clientRetriever.PerRetryPolicies = append(clientRetriever.PerRetryPolicies, player)
+	}
+
+	return clientRetriever, nil
+}
+
+// toolRegistry adds tools to the server unless the tools configuration disables them.
+type toolRegistry struct {
+	server          *server.MCPServer
+	clientRetriever tools.ServiceClientRetriever
+	config          ToolsConfig
+	count           int
+}
+
+func (registry *toolRegistry) add(category string, constructors ...func(tools.ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc)) {
+	for _, constructor := range constructors {
+		tool, handler := constructor(registry.clientRetriever)
+		if !registry.config.enabled(category, tool) {
+			continue
+		}
+		registry.server.AddTool(tool, handler)
+		registry.count++
+	}
+}
+
+func (registry *toolRegistry) registered(names ...string) bool {
+	for _, name := range names {
+		if registry.server.GetTool(name) == nil {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const SERVICE_NAME = "azure-nexus-mcp-server"
+
+// LoggingConfig selects where and how verbosely the server logs. Logs never go to stdout, which
+// carries the MCP protocol.
+type LoggingConfig struct {
+	// Level is one of debug, info, warn or error. It defaults to info.
+	Level string `json:"level"`
+	// File receives the logs instead of stderr when set.
+	File string `json:"file"`
+	// JSON writes the logs as JSON lines instead of text.
+	JSON bool `json:"json"`
+}
+
+// TracingConfig selects where OpenTelemetry spans are exported. Tracing is off when neither is set.
+type TracingConfig struct {
+	// OTLPEndpoint is the URL of an OTLP/HTTP collector, e.g. http://localhost:4318.
+	OTLPEndpoint string `json:"otlpEndpoint"`
+	// File receives the spans as JSON, for when no collector is available.
+	File string `json:"file"`
+}
+
+// setupLogging installs the default slog logger and returns a function that closes the log file.
+func setupLogging(config LoggingConfig) (func(), error) {
+	var level slog.Level
+	if config.Level != "" {
+		if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level '%s': %v", config.Level, err)
+		}
+	}
+
+	var writer io.Writer = os.Stderr
+	closeLog := func() {}
+	if config.File != "" {
+		file, err := os.OpenFile(config.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file %s: %v", config.File, err)
+		}
+		writer = file
+		closeLog = func() { file.Close() }
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(writer, options)
+	if config.JSON {
+		handler = slog.NewJSONHandler(writer, options)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	return closeLog, nil
+}
+
+// setupTracing registers a tracer provider exporting to the configured destinations and returns a
+// function that flushes and stops it.
+func setupTracing(ctx context.Context, config TracingConfig) (func(context.Context) error, error) {
+	var options []sdktrace.TracerProviderOption
+	var files []io.Closer
+
+	if config.OTLPEndpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimSuffix(config.OTLPEndpoint, "/")+"/v1/traces"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	if config.File != "" {
+		file, err := os.OpenFile(config.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file %s: %v", config.File, err)
+		}
+		files = append(files, file)
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace file exporter: %v", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	if len(options) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options = append(options, sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(SERVICE_NAME))))
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, file := range files {
+			file.Close()
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	OUTCOME_SUCCESS    = "success"
+	OUTCOME_TOOL_ERROR = "tool_error"
+	OUTCOME_ERROR      = "error"
+)
+
+// AuditEntry is one line of the audit log.
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Tool      string    `json:"tool"`
+	// Arguments are scrubbed of secrets the same way as cassettes.
+	Arguments      any      `json:"arguments,omitempty"`
+	Caller         string   `json:"caller,omitempty"`
+	Session        string   `json:"session,omitempty"`
+	CorrelationIDs []string `json:"correlationIds,omitempty"`
+	DurationMs     int64    `json:"durationMs"`
+	Outcome        string   `json:"outcome"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// AuditLogger appends an AuditEntry for every tool call to a JSONL file and, optionally, to syslog.
+type AuditLogger struct {
+	mu     sync.Mutex
+	file   *os.File
+	syslog auditWriter
+}
+
+type auditWriter interface {
+	Write(line []byte) error
+	Close() error
+}
+
+type callerKey struct{}
+
+type correlationKey struct{}
+
+type correlationIDs struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func NewAuditLogger(path string, useSyslog bool) (*AuditLogger, error) {
+	logger := &AuditLogger{}
+
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log %s: %v", path, err)
+		}
+		logger.file = file
+	}
+
+	if useSyslog {
+		writer, err := newSyslogWriter()
+		if err != nil {
+			logger.Close()
+			return nil, fmt.Errorf("failed to connect to syslog: %v", err)
+		}
+		logger.syslog = writer
+	}
+
+	return logger, nil
+}
+
+// Middleware records every tool call handled by the server.
+func (logger *AuditLogger) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ids := &correlationIDs{}
+			ctx = context.WithValue(ctx, correlationKey{}, ids)
+
+			entry := AuditEntry{
+				Timestamp: time.Now().UTC(),
+				Tool:      request.Params.Name,
+				Arguments: sanitizeArguments(request.Params.Arguments),
+				Caller:    callerFromContext(ctx),
+				Session:   sessionID(ctx),
+			}
+
+			result, err := next(ctx, request)
+
+			entry.DurationMs = time.Since(entry.Timestamp).Milliseconds()
+			entry.CorrelationIDs = ids.list()
+			entry.Outcome, entry.Error = callOutcome(result, err)
+			logger.write(entry)
+
+			return result, err
+		}
+	}
+}
+
+func (logger *AuditLogger) write(entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.Error("failed to marshal audit entry", "error", err)
+		return
+	}
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if logger.file != nil {
+		if _, err := logger.file.Write(append(line, '\n')); err != nil {
+			slog.Error("failed to write audit log", "error", err)
+		}
+	}
+	if logger.syslog != nil {
+		if err := logger.syslog.Write(line); err != nil {
+			slog.Error("failed to write audit entry to syslog", "error", err)
+		}
+	}
+}
+
+func (logger *AuditLogger) Close() error {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	var err error
+	if logger.file != nil {
+		err = logger.file.Close()
+	}
+	if logger.syslog != nil {
+		if syslogErr := logger.syslog.Close(); err == nil {
+			err = syslogErr
+		}
+	}
+	return err
+}
+
+// WithCaller attaches the identity of the caller to the context of a request, so that it is
+// recorded in the audit log. The transport serving the request sets it.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func callerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// CorrelationPolicy is a pipeline policy that collects the ARM correlation IDs of the requests made
+// during a tool call, for the audit log.
+type CorrelationPolicy struct{}
+
+func (CorrelationPolicy) Do(req *policy.Request) (*http.Response, error) {
+	resp, err := req.Next()
+	if resp == nil {
+		return resp, err
+	}
+
+	if ids, ok := req.Raw().Context().Value(correlationKey{}).(*correlationIDs); ok {
+		ids.add(resp.Header.Get("x-ms-correlation-request-id"))
+	}
+	return resp, err
+}
+
+func (ids *correlationIDs) add(id string) {
+	if id == "" {
+		return
+	}
+
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+	for _, existing := range ids.ids {
+		if existing == id {
+			return
+		}
+	}
+	ids.ids = append(ids.ids, id)
+}
+
+func (ids *correlationIDs) list() []string {
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+	return append([]string(nil), ids.ids...)
+}
+
+// sanitizeArguments returns a scrubbed copy of the arguments, leaving the request untouched.
+func sanitizeArguments(arguments any) any {
+	argsJson, err := json.Marshal(arguments)
+	if err != nil {
+		return nil
+	}
+
+	var copied any
+	if err := json.Unmarshal(argsJson, &copied); err != nil {
+		return nil
+	}
+	return scrubValue(copied)
+}
+
+// callOutcome classifies the result of a tool call and returns its error message, if any.
+func callOutcome(result *mcp.CallToolResult, err error) (string, string) {
+	switch {
+	case err != nil:
+		return OUTCOME_ERROR, err.Error()
+	case result != nil && result.IsError:
+		return OUTCOME_TOOL_ERROR, resultText(result)
+	default:
+		return OUTCOME_SUCCESS, ""
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
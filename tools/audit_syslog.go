@@ -0,0 +1,25 @@
+//go:build !windows && !plan9
+
+package tools
+
+import "log/syslog"
+
+type syslogWriter struct {
+	writer *syslog.Writer
+}
+
+func newSyslogWriter() (auditWriter, error) {
+	writer, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_USER, "azure-nexus-mcp-server")
+	if err != nil {
+		return nil, err
+	}
+	return syslogWriter{writer: writer}, nil
+}
+
+func (w syslogWriter) Write(line []byte) error {
+	return w.writer.Notice(string(line))
+}
+
+func (w syslogWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package tools
+
+import "errors"
+
+func newSyslogWriter() (auditWriter, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
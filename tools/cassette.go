@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const SCRUBBED_VALUE = "REDACTED"
+
+// Headers that are never written to a cassette.
+var scrubbedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Ms-Authorization-Auxiliary",
+}
+
+// JSON body fields whose values are never written to a cassette.
+var scrubbedBodyFields = []string{
+	"password",
+	"secret",
+	"token",
+	"accessKey",
+	"primaryKey",
+	"secondaryKey",
+	"sharedKey",
+}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int                 `json:"statusCode"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+// A recorded cassette is the header, the interactions and the trailer, laid out the way
+// json.MarshalIndent lays out a Cassette.
+const (
+	cassetteHeader  = "{\n  \"interactions\": ["
+	cassetteTrailer = "\n  ]\n}\n"
+)
+
+// CassetteRecorder is a pipeline policy that writes every ARM exchange to a cassette file.
+type CassetteRecorder struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+	// end is the offset of the trailer, where the next interaction is written
+	end          int64
+	interactions int
+}
+
+func NewCassetteRecorder(path string) (*CassetteRecorder, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette %s: %v", path, err)
+	}
+	if _, err := file.WriteString(cassetteHeader + cassetteTrailer); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write cassette %s: %v", path, err)
+	}
+	return &CassetteRecorder{path: path, file: file, end: int64(len(cassetteHeader))}, nil
+}
+
+func (recorder *CassetteRecorder) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	var reqBody []byte
+	if raw.Body != nil {
+		// the request body is seekable, so read it and rewind it for the next policy
+		var err error
+		reqBody, err = io.ReadAll(raw.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %v", err)
+		}
+		if err := req.RewindBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %v", err)
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  raw.Method,
+			URL:     raw.URL.String(),
+			Headers: scrubHeaders(raw.Header),
+			Body:    scrubBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubHeaders(resp.Header),
+			Body:       scrubBody(respBody),
+		},
+	}
+
+	// the request reached ARM, so a failure to record it must not hide the response
+	if err := recorder.record(interaction); err != nil {
+		slog.WarnContext(raw.Context(), "failed to record ARM exchange", "cassette", recorder.path, "method", raw.Method, "url", raw.URL.String(), "error", err)
+	}
+
+	return resp, nil
+}
+
+// record appends the interaction to the cassette by overwriting the trailer with the interaction
+// and the trailer, so every exchange costs one write of its own size and the file is a complete
+// cassette after each of them. When the write fails the trailer is restored at the previous end.
+func (recorder *CassetteRecorder) record(interaction Interaction) error {
+	data, err := json.MarshalIndent(interaction, "    ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal interaction: %v", err)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	separator := "\n    "
+	if recorder.interactions > 0 {
+		separator = ",\n    "
+	}
+	entry := append([]byte(separator), data...)
+	if _, err := recorder.file.WriteAt(append(entry, cassetteTrailer...), recorder.end); err != nil {
+		recorder.file.WriteAt([]byte(cassetteTrailer), recorder.end)
+		recorder.file.Truncate(recorder.end + int64(len(cassetteTrailer)))
+		return fmt.Errorf("failed to write cassette: %v", err)
+	}
+	recorder.end += int64(len(entry))
+	recorder.interactions++
+	return nil
+}
+
+// CassettePlayer is a pipeline policy that serves recorded ARM responses instead of calling Azure.
+// Interactions are matched by method and URL and served in recorded order; once a request's
+// recordings are exhausted the last one is served again.
+type CassettePlayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	served       map[string]int
+}
+
+func LoadCassettePlayer(path string) (*CassettePlayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %v", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cassette %s: %v", path, err)
+	}
+
+	player := &CassettePlayer{
+		interactions: make(map[string][]Interaction),
+		served:       make(map[string]int),
+	}
+	for _, interaction := range cassette.Interactions {
+		key := interactionKey(interaction.Request.Method, interaction.Request.URL)
+		player.interactions[key] = append(player.interactions[key], interaction)
+	}
+	return player, nil
+}
+
+func (player *CassettePlayer) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	key := interactionKey(raw.Method, raw.URL.String())
+
+	player.mu.Lock()
+	recorded, ok := player.interactions[key]
+	if !ok {
+		player.mu.Unlock()
+		return nil, fmt.Errorf("no recorded interaction for %s %s", raw.Method, raw.URL.String())
+	}
+	index := player.served[key]
+	if index >= len(recorded) {
+		index = len(recorded) - 1
+	}
+	player.served[key] = index + 1
+	interaction := recorded[index]
+	player.mu.Unlock()
+
+	header := http.Header{}
+	for name, values := range interaction.Response.Headers {
+		header[name] = append([]string(nil), values...)
+	}
+	// replay without waiting out the recorded polling and throttling delays
+	header.Del("Retry-After")
+	header.Del("X-Ms-Retry-After-Ms")
+	header.Set("Retry-After-Ms", "1")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       raw,
+	}, nil
+}
+
+func interactionKey(method, url string) string {
+	return method + " " + url
+}
+
+// ReplayCredential is a static credential used while replaying a cassette, so no az login is needed.
+type ReplayCredential struct{}
+
+func (ReplayCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: SCRUBBED_VALUE}, nil
+}
+
+func scrubHeaders(header http.Header) map[string][]string {
+	scrubbed := make(map[string][]string, len(header))
+	for name, values := range header {
+		scrubbed[name] = values
+	}
+	for _, name := range scrubbedHeaders {
+		if _, ok := scrubbed[http.CanonicalHeaderKey(name)]; ok {
+			scrubbed[http.CanonicalHeaderKey(name)] = []string{SCRUBBED_VALUE}
+		}
+	}
+	return scrubbed
+}
+
+func scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	scrubbed, err := json.Marshal(scrubValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(scrubbed)
+}
+
+func scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if isScrubbedField(key) {
+				v[key] = SCRUBBED_VALUE
+				continue
+			}
+			v[key] = scrubValue(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = scrubValue(child)
+		}
+	}
+	return value
+}
+
+func isScrubbedField(key string) bool {
+	for _, field := range scrubbedBodyFields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
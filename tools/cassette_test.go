@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// fakeTransport answers every request with the path of the request as the body.
+type fakeTransport struct{}
+
+func (fakeTransport) Do(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"path":"` + r.URL.Path + `"}`)),
+		Request:    r,
+	}, nil
+}
+
+func testCassetteGet(t *testing.T, pipelinePolicy policy.Policy, transport policy.Transporter, path string) (*http.Response, error) {
+	t.Helper()
+	pipeline := runtime.NewPipeline("test", "v0.0.1", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerRetryPolicies: []policy.Policy{pipelinePolicy},
+		Transport:        transport,
+		Retry:            policy.RetryOptions{MaxRetries: -1},
+	})
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://management.azure.com"+path)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Raw().Header.Set("Authorization", "Bearer secret")
+	return pipeline.Do(req)
+}
+
+func TestCassetteRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := NewCassetteRecorder(path)
+	if err != nil {
+		t.Fatalf("NewCassetteRecorder failed: %v", err)
+	}
+
+	paths := []string{"/a", "/b", "/a"}
+	for i, requestPath := range paths {
+		if _, err := testCassetteGet(t, recorder, fakeTransport{}, requestPath); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+
+		// the file is a complete cassette after every exchange
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read cassette: %v", err)
+		}
+		var cassette Cassette
+		if err := json.Unmarshal(data, &cassette); err != nil {
+			t.Fatalf("cassette after %d exchanges is not valid JSON: %v\n%s", i+1, err, data)
+		}
+		if len(cassette.Interactions) != i+1 {
+			t.Fatalf("cassette has %d interactions, want %d", len(cassette.Interactions), i+1)
+		}
+		if authorization := cassette.Interactions[i].Request.Headers["Authorization"]; len(authorization) != 1 || authorization[0] != SCRUBBED_VALUE {
+			t.Errorf("authorization header = %v, want it scrubbed", authorization)
+		}
+		if i == len(paths)-1 {
+			indented, _ := json.MarshalIndent(cassette, "", "  ")
+			if string(data) != string(indented)+"\n" {
+				t.Errorf("cassette is not laid out like json.MarshalIndent:\n%s", data)
+			}
+		}
+	}
+
+	player, err := LoadCassettePlayer(path)
+	if err != nil {
+		t.Fatalf("LoadCassettePlayer failed: %v", err)
+	}
+	resp, err := testCassetteGet(t, player, nil, "/b")
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"path":"/b"}` {
+		t.Errorf("replayed body = %s, want the recorded one", body)
+	}
+}
+
+func TestCassetteRecorderWriteFailure(t *testing.T) {
+	recorder, err := NewCassetteRecorder(filepath.Join(t.TempDir(), "cassette.json"))
+	if err != nil {
+		t.Fatalf("NewCassetteRecorder failed: %v", err)
+	}
+	recorder.file.Close()
+
+	resp, err := testCassetteGet(t, recorder, fakeTransport{}, "/a")
+	if err != nil {
+		t.Fatalf("request failed because it could not be recorded: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want the response of ARM", resp.StatusCode)
+	}
+}
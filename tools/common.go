@@ -2,18 +2,38 @@ package tools
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 )
 
 type ClientRetriever interface {
-	Get() (*azidentity.AzureCLICredential, error)
+	Get() (azcore.TokenCredential, error)
+	ClientOptions() *arm.ClientOptions
 }
 
 type ServiceClientRetriever struct {
+	// Credential replaces the Azure CLI credential when set, e.g. while replaying a cassette.
+	Credential azcore.TokenCredential
+	// PerRetryPolicies are added to the pipeline of every ARM client created by the tools.
+	PerRetryPolicies []policy.Policy
+	// SkipConfirmation lets destructive tools run without asking the user, e.g. for clients that do
+	// not support elicitation.
+	SkipConfirmation bool
+	// Protection lists the resources that mutating tools refuse to change.
+	Protection *ProtectionPolicy
+	// Context holds the session defaults used by set_context and the nexus://context resource.
+	Context *ContextStore
 }
 
-func (retriever ServiceClientRetriever) Get() (*azidentity.AzureCLICredential, error) {
+func (retriever ServiceClientRetriever) Get() (azcore.TokenCredential, error) {
+	if retriever.Credential != nil {
+		return retriever.Credential, nil
+	}
+
 	client, err := azidentity.NewAzureCLICredential(nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating az cli client: %v", err)
@@ -22,3 +42,68 @@ func (retriever ServiceClientRetriever) Get() (*azidentity.AzureCLICredential, e
 	return client, nil
 
 }
+
+func (retriever ServiceClientRetriever) ClientOptions() *arm.ClientOptions {
+	if len(retriever.PerRetryPolicies) == 0 {
+		return nil
+	}
+
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			PerRetryPolicies: retriever.PerRetryPolicies,
+		},
+	}
+}
+
+// stringValue dereferences an optional SDK string, returning "unknown" when it is not set.
+func stringValue[T ~string](value *T) string {
+	if value == nil {
+		return "unknown"
+	}
+	return string(*value)
+}
+
+// TargetResource describes the resource a mutating tool call acts on.
+type TargetResource struct {
+	SubscriptionID string
+	ResourceGroup  string
+	ResourceType   string
+	Name           string
+	// ParentName is the L3 isolation domain of internal and external networks.
+	ParentName string
+	// FabricID is the network fabric given in the properties of a create call, if any.
+	FabricID *string
+}
+
+// ID returns the ARM ID of the target.
+func (target TargetResource) ID() string {
+	if target.ResourceType == RESOURCE_GROUP_RESOURCE_TYPE {
+		return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", target.SubscriptionID, target.Name)
+	}
+
+	// the type is the provider namespace followed by one segment per level, e.g.
+	// Microsoft.ManagedNetworkFabric/l3IsolationDomains/internalNetworks
+	segments := strings.Split(target.ResourceType, "/")
+	names := []string{target.Name}
+	if target.ParentName != "" {
+		names = []string{target.ParentName, target.Name}
+	}
+
+	id := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s", target.SubscriptionID, target.ResourceGroup, segments[0])
+	for i, segment := range segments[1:] {
+		if i < len(names) {
+			id += fmt.Sprintf("/%s/%s", segment, names[i])
+		}
+	}
+	return id
+}
+
+// ParentID returns the ARM ID of the L3 isolation domain of an internal or external network.
+func (target TargetResource) ParentID() string {
+	return TargetResource{
+		SubscriptionID: target.SubscriptionID,
+		ResourceGroup:  target.ResourceGroup,
+		ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+		Name:           target.ParentName,
+	}.ID()
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	COMPLETION_CACHE_TTL = 30 * time.Second
+	// The MCP specification allows at most 100 values per completion.
+	MAX_COMPLETION_VALUES = 100
+)
+
+// Completer suggests existing names for the arguments of the prompts and resource templates. Names
+// are listed from ARM in the subscription and resource group of the request, or of the session
+// context, and cached briefly since clients ask again on every keystroke.
+type Completer struct {
+	clientRetriever ServiceClientRetriever
+
+	mu    sync.Mutex
+	cache map[string]cachedNames
+}
+
+type cachedNames struct {
+	names   []string
+	expires time.Time
+}
+
+func NewCompleter(clientRetriever ServiceClientRetriever) *Completer {
+	return &Completer{
+		clientRetriever: clientRetriever,
+		cache:           make(map[string]cachedNames),
+	}
+}
+
+// CompletePromptArgument completes the subscriptionId, resourceGroupName and fabricName arguments
+// of the prompts. The other arguments name resources that are yet to be created.
+func (completer *Completer) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	sessionContext := completer.sessionContext(ctx)
+	subscriptionId := firstNonEmpty(completeContext.Arguments["subscriptionId"], sessionContext.SubscriptionID)
+	resourceGroupName := firstNonEmpty(completeContext.Arguments["resourceGroupName"], sessionContext.ResourceGroup)
+
+	switch argument.Name {
+	case "subscriptionId":
+		return completer.complete(ctx, argument.Value, "subscriptions", completer.listSubscriptions)
+	case "resourceGroupName":
+		return completer.completeResourceGroups(ctx, argument.Value, subscriptionId)
+	case "fabricName":
+		fabrics, _ := findNexusResourceType("fabrics/{name}")
+		return completer.completeResources(ctx, argument.Value, fabrics, subscriptionId, resourceGroupName, "")
+	}
+
+	return &mcp.Completion{Values: []string{}}, nil
+}
+
+// CompleteResourceArgument completes the variables of the nexus:// resource templates.
+func (completer *Completer) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	resourceType, ok := findNexusResourceTemplate(uri)
+	if !ok {
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	sessionContext := completer.sessionContext(ctx)
+	subscriptionId := firstNonEmpty(completeContext.Arguments["subscription"], sessionContext.SubscriptionID)
+	resourceGroupName := firstNonEmpty(completeContext.Arguments["rg"], sessionContext.ResourceGroup)
+
+	switch argument.Name {
+	case "subscription":
+		return completer.complete(ctx, argument.Value, "subscriptions", completer.listSubscriptions)
+	case "rg":
+		return completer.completeResourceGroups(ctx, argument.Value, subscriptionId)
+	case "name":
+		// child templates name their parent first, e.g. the L3 isolation domain of an internal network
+		if parent, ok := resourceType.parent(); ok {
+			resourceType = parent
+		}
+		return completer.completeResources(ctx, argument.Value, resourceType, subscriptionId, resourceGroupName, "")
+	case "child":
+		name := completeContext.Arguments["name"]
+		if name == "" {
+			return &mcp.Completion{Values: []string{}}, nil
+		}
+		return completer.completeResources(ctx, argument.Value, resourceType, subscriptionId, resourceGroupName, name)
+	}
+
+	return &mcp.Completion{Values: []string{}}, nil
+}
+
+func (completer *Completer) completeResourceGroups(ctx context.Context, prefix, subscriptionId string) (*mcp.Completion, error) {
+	if subscriptionId == "" {
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	return completer.complete(ctx, prefix, "resourcegroups/"+subscriptionId, func(ctx context.Context, cred azcore.TokenCredential) ([]string, error) {
+		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, completer.clientRetriever.ClientOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource groups client: %v", err)
+		}
+
+		pager := client.NewListPager(nil)
+
+		names := []string{}
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, resourceGroup := range page.Value {
+				if resourceGroup.Name != nil {
+					names = append(names, *resourceGroup.Name)
+				}
+			}
+		}
+		return names, nil
+	})
+}
+
+func (completer *Completer) completeResources(ctx context.Context, prefix string, resourceType nexusResourceType, subscriptionId, resourceGroupName, name string) (*mcp.Completion, error) {
+	if subscriptionId == "" || resourceGroupName == "" {
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	key := strings.Join([]string{resourceType.path, subscriptionId, strings.ToLower(resourceGroupName), strings.ToLower(name)}, "/")
+	return completer.complete(ctx, prefix, key, func(ctx context.Context, cred azcore.TokenCredential) ([]string, error) {
+		return resourceType.list(ctx, cred, completer.clientRetriever.ClientOptions(), subscriptionId, resourceGroupName, name)
+	})
+}
+
+func (completer *Completer) listSubscriptions(ctx context.Context, cred azcore.TokenCredential) ([]string, error) {
+	subscriptions, err := getSubscriptions(ctx, completer.clientRetriever, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.SubscriptionID != nil {
+			ids = append(ids, *subscription.SubscriptionID)
+		}
+	}
+	return ids, nil
+}
+
+// complete returns the names under key that start with prefix, ignoring case. Names are listed
+// again once the cached ones expire. A failed listing is logged and completes nothing, so it does
+// not get in the way of typing.
+func (completer *Completer) complete(ctx context.Context, prefix, key string, list func(context.Context, azcore.TokenCredential) ([]string, error)) (*mcp.Completion, error) {
+	names, err := completer.names(ctx, key, list)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list completions", "key", key, "error", err)
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	values := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			values = append(values, name)
+		}
+	}
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > MAX_COMPLETION_VALUES {
+		completion.Values = values[:MAX_COMPLETION_VALUES]
+		completion.HasMore = true
+	}
+	return completion, nil
+}
+
+func (completer *Completer) names(ctx context.Context, key string, list func(context.Context, azcore.TokenCredential) ([]string, error)) ([]string, error) {
+	completer.mu.Lock()
+	cached, ok := completer.cache[key]
+	completer.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.names, nil
+	}
+
+	cred, err := completer.clientRetriever.Get()
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials: %v", err)
+	}
+
+	names, err := list(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	completer.mu.Lock()
+	defer completer.mu.Unlock()
+	completer.cache[key] = cachedNames{names: names, expires: time.Now().Add(COMPLETION_CACHE_TTL)}
+	for key, cached := range completer.cache {
+		if time.Now().After(cached.expires) {
+			delete(completer.cache, key)
+		}
+	}
+
+	return names, nil
+}
+
+func (completer *Completer) sessionContext(ctx context.Context) SessionContext {
+	if completer.clientRetriever.Context == nil {
+		return SessionContext{}
+	}
+	return completer.clientRetriever.Context.Get(ctx)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
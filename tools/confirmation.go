@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Maximum number of dependent resources listed in a confirmation request.
+const MAX_LISTED_DEPENDENTS = 20
+
+// confirmAction asks the user to confirm a destructive or far-reaching action through an MCP
+// elicitation request. It returns a nil result when the action may proceed, and a tool error
+// result when it must be aborted, including when the client cannot be asked.
+func confirmAction(ctx context.Context, clientRetriever ServiceClientRetriever, action, impact string) (*mcp.CallToolResult, error) {
+	if clientRetriever.SkipConfirmation {
+		return nil, nil
+	}
+
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s was aborted: no MCP session is available to ask for confirmation.", action)), nil
+	}
+
+	message := fmt.Sprintf("%s?\n\n%s", action, impact)
+	result, err := mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: message,
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"title":       "Confirm",
+						"description": "Set to true to proceed with this action.",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s was aborted: confirmation could not be requested from the user: %v. Use an MCP client that supports elicitation, or start the server with -skip-confirmation.", action, err)), nil
+	}
+
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return mcp.NewToolResultError(fmt.Sprintf("%s was aborted: the user responded with '%s'.", action, result.Action)), nil
+	}
+
+	content, ok := result.Content.(map[string]any)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("%s was aborted: the confirmation response was not understood.", action)), nil
+	}
+	if confirmed, ok := content["confirm"].(bool); !ok || !confirmed {
+		return mcp.NewToolResultError(fmt.Sprintf("%s was aborted: the user did not confirm.", action)), nil
+	}
+
+	return nil, nil
+}
+
+func resourceGroupDeleteImpact(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, name string) string {
+	client, err := armresources.NewClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	var dependents []string
+	pager := client.NewListByResourceGroupPager(name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Sprintf("Impact could not be determined: %v", err)
+		}
+		for _, resource := range page.Value {
+			dependents = append(dependents, fmt.Sprintf("%s (%s)", stringValue(resource.Name), stringValue(resource.Type)))
+		}
+	}
+
+	return "Every resource in the resource group will be deleted.\n" + formatDependents(dependents)
+}
+
+func l3IsolationDomainDeleteImpact(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, resourceGroupName, name string) string {
+	isdClient, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	isd, err := isdClient.Get(ctx, resourceGroupName, name, nil)
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	impact := fmt.Sprintf("- Administrative State: %s\n", stringValue(isd.Properties.AdministrativeState))
+	impact += fmt.Sprintf("- Network Fabric: %s\n", getNameFromID(stringValue(isd.Properties.NetworkFabricID)))
+	if isd.Properties.AdministrativeState != nil && *isd.Properties.AdministrativeState == armmanagednetworkfabric.AdministrativeStateEnabled {
+		impact += "The isolation domain is ENABLED and carrying configuration on the fabric.\n"
+	}
+
+	var dependents []string
+	internalClient, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return impact + fmt.Sprintf("Dependent resources could not be determined: %v", err)
+	}
+	internalPager := internalClient.NewListByL3IsolationDomainPager(resourceGroupName, name, nil)
+	for internalPager.More() {
+		page, err := internalPager.NextPage(ctx)
+		if err != nil {
+			return impact + fmt.Sprintf("Dependent resources could not be determined: %v", err)
+		}
+		for _, network := range page.Value {
+			dependents = append(dependents, fmt.Sprintf("%s (internal network)", stringValue(network.Name)))
+		}
+	}
+
+	externalClient, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return impact + fmt.Sprintf("Dependent resources could not be determined: %v", err)
+	}
+	externalPager := externalClient.NewListByL3IsolationDomainPager(resourceGroupName, name, nil)
+	for externalPager.More() {
+		page, err := externalPager.NextPage(ctx)
+		if err != nil {
+			return impact + fmt.Sprintf("Dependent resources could not be determined: %v", err)
+		}
+		for _, network := range page.Value {
+			dependents = append(dependents, fmt.Sprintf("%s (external network)", stringValue(network.Name)))
+		}
+	}
+
+	return impact + formatDependents(dependents)
+}
+
+func l2IsolationDomainDisableImpact(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, resourceGroupName, name string) string {
+	client, err := armmanagednetworkfabric.NewL2IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	isd, err := client.Get(ctx, resourceGroupName, name, nil)
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	impact := fmt.Sprintf("- Administrative State: %s\n", stringValue(isd.Properties.AdministrativeState))
+	impact += fmt.Sprintf("- Network Fabric: %s\n", getNameFromID(stringValue(isd.Properties.NetworkFabricID)))
+	if isd.Properties.VlanID != nil {
+		impact += fmt.Sprintf("- VLAN ID: %d\n", *isd.Properties.VlanID)
+	}
+	if isd.Properties.AdministrativeState != nil && *isd.Properties.AdministrativeState == armmanagednetworkfabric.AdministrativeStateEnabled {
+		impact += "Traffic on this VLAN will stop once the isolation domain is disabled.\n"
+	}
+
+	return impact
+}
+
+func networkDeviceRebootImpact(device armmanagednetworkfabric.NetworkDevice) string {
+	impact := fmt.Sprintf("- Role: %s\n", stringValue(device.Properties.NetworkDeviceRole))
+	impact += fmt.Sprintf("- Host Name: %s\n", stringValue(device.Properties.HostName))
+	impact += fmt.Sprintf("- Serial Number: %s\n", stringValue(device.Properties.SerialNumber))
+	impact += fmt.Sprintf("- Network Rack: %s\n", getNameFromID(stringValue(device.Properties.NetworkRackID)))
+	impact += "Traffic through the device is interrupted while it reboots.\n"
+
+	return impact
+}
+
+func networkFabricCommitImpact(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, resourceGroupName, fabricName string) string {
+	client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	fabric, err := client.Get(ctx, resourceGroupName, fabricName, nil)
+	if err != nil {
+		return fmt.Sprintf("Impact could not be determined: %v", err)
+	}
+
+	impact := fmt.Sprintf("- Administrative State: %s\n", stringValue(fabric.Properties.AdministrativeState))
+	impact += fmt.Sprintf("- Configuration State: %s\n", stringValue(fabric.Properties.ConfigurationState))
+	impact += fmt.Sprintf("- Racks: %d\n", len(fabric.Properties.Racks))
+	impact += fmt.Sprintf("- L2 Isolation Domains: %d\n", len(fabric.Properties.L2IsolationDomains))
+	impact += fmt.Sprintf("- L3 Isolation Domains: %d\n", len(fabric.Properties.L3IsolationDomains))
+	impact += "All pending configuration changes will be pushed to every device in the fabric.\n"
+
+	return impact
+}
+
+func routePolicyBuildImpact(resources []buildResource) string {
+	impact := fmt.Sprintf("%d resources will be created, the route policy last:\n", len(resources))
+	for i, resource := range resources {
+		if i == MAX_LISTED_DEPENDENTS {
+			impact += fmt.Sprintf("- ... and %d more\n", len(resources)-MAX_LISTED_DEPENDENTS)
+			break
+		}
+		impact += fmt.Sprintf("- %s (%s)\n", resource.target.Name, resource.target.ResourceType)
+	}
+	return impact
+}
+
+func formatDependents(dependents []string) string {
+	if len(dependents) == 0 {
+		return "No dependent resources were found.\n"
+	}
+
+	result := fmt.Sprintf("Dependent resources (%d):\n", len(dependents))
+	for i, dependent := range dependents {
+		if i == MAX_LISTED_DEPENDENTS {
+			result += fmt.Sprintf("- ... and %d more\n", len(dependents)-MAX_LISTED_DEPENDENTS)
+			break
+		}
+		result += fmt.Sprintf("- %s\n", dependent)
+	}
+	return strings.TrimSuffix(result, "\n") + "\n"
+}
@@ -3,51 +3,60 @@ package tools
 const (
 	CREATE_IP_PREFIX_TOOL_NAME          = "create_ipprefix"
 	IPREFIX_PARAMETER_DESCRIPTION       = "The name of the IP prefix to be created. If not available, ask the user to provide the name. Do not use a random name of your choice"
-	IPREFIX_LOCATION_DESCRIPTION        = "The location of the IP prefix."
-	IPREFIX_PROPERTIES_DESCRIPTION      = "The properties of the IP prefix, including IP prefix rules. This should be a JSON string."
+	IPREFIX_LOCATION_DESCRIPTION        = "The location of the IP prefix. Defaults to the location of the session context."
+	IPREFIX_PROPERTIES_DESCRIPTION      = "The properties of the IP prefix, including IP prefix rules. This should be a JSON object."
 	IPREFIX_IP_DESCRIPTION              = "The IP version(s) for the IP prefix, as a JSON string array e.g., [\"ipv6\"]."
-	IPREFIX_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	IPREFIX_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	IPREFIX_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	IPREFIX_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	DELETE_IP_PREFIX_TOOL_NAME          = "delete_ipprefix"
 	PATCH_IP_PREFIX_TOOL_NAME           = "patch_ipprefix"
 	GET_IP_PREFIX_TOOL_NAME             = "get_ipprefix"
 
 	CREATE_IP_COMMUNITY_TOOL_NAME           = "create_ipcommunity"
 	IPCOMMUNITY_PARAMETER_DESCRIPTION       = "The name of the IP community to be created."
-	IPCOMMUNITY_LOCATION_DESCRIPTION        = "The location of the IP community."
-	IPCOMMUNITY_PROPERTIES_DESCRIPTION      = "The properties of the IP community, including IP community rules. This should be a JSON string."
-	IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	IPCOMMUNITY_LOCATION_DESCRIPTION        = "The location of the IP community. Defaults to the location of the session context."
+	IPCOMMUNITY_PROPERTIES_DESCRIPTION      = "The properties of the IP community, including IP community rules. This should be a JSON object."
+	IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	DELETE_IP_COMMUNITY_TOOL_NAME           = "delete_ipcommunity"
 	PATCH_IP_COMMUNITY_TOOL_NAME            = "patch_ipcommunity"
 	GET_IP_COMMUNITY_TOOL_NAME              = "get_ipcommunity"
 
 	CREATE_IP_EXT_COMMUNITY_TOOL_NAME          = "create_ipextcommunity"
 	IPEXTCOMMUNITY_PARAMETER_DESCRIPTION       = "The name of the IP extended community to be created."
-	IPEXTCOMMUNITY_LOCATION_DESCRIPTION        = "The location of the IP extended community."
-	IPEXTCOMMUNITY_PROPERTIES_DESCRIPTION      = "The properties of the IP extended community, including IP extended community rules. This should be a JSON string."
-	IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	IPEXTCOMMUNITY_LOCATION_DESCRIPTION        = "The location of the IP extended community. Defaults to the location of the session context."
+	IPEXTCOMMUNITY_PROPERTIES_DESCRIPTION      = "The properties of the IP extended community, including IP extended community rules. This should be a JSON object."
+	IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	DELETE_IP_EXT_COMMUNITY_TOOL_NAME          = "delete_ipextcommunity"
 	PATCH_IP_EXT_COMMUNITY_TOOL_NAME           = "patch_ipextcommunity"
 	GET_IP_EXT_COMMUNITY_TOOL_NAME             = "get_ipextcommunity"
 
 	CREATE_ROUTE_POLICY_TOOL_NAME            = "create_routepolicy"
 	ROUTE_POLICY_PARAMETER_DESCRIPTION       = "The name of the Route Policy to be created."
-	ROUTE_POLICY_LOCATION_DESCRIPTION        = "The location of the Route Policy."
-	ROUTE_POLICY_PROPERTIES_DESCRIPTION      = "The properties of the Route Policy, including statements. This should be a JSON string."
-	ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	ROUTE_POLICY_LOCATION_DESCRIPTION        = "The location of the Route Policy. Defaults to the location of the session context."
+	ROUTE_POLICY_PROPERTIES_DESCRIPTION      = "The properties of the Route Policy, including statements. This should be a JSON object." + REFERENCE_PROPERTIES_DESCRIPTION
+	ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	DELETE_ROUTE_POLICY_TOOL_NAME            = "delete_routepolicy"
 	PATCH_ROUTE_POLICY_TOOL_NAME             = "patch_routepolicy"
 	GET_ROUTE_POLICY_TOOL_NAME               = "get_routepolicy"
+	ANALYZE_ROUTE_POLICY_TOOL_NAME           = "analyze_routepolicy"
+	SIMULATE_ROUTE_POLICY_TOOL_NAME          = "simulate_routepolicy"
+	ROUTE_PREFIX_DESCRIPTION                 = "The prefix of the route to evaluate, e.g. 10.2.32.0/26."
+	ROUTE_COMMUNITIES_DESCRIPTION            = "The communities the route carries, as a JSON string array e.g. [\"65001:100\", \"no-export\"]."
+	ROUTE_EXT_COMMUNITIES_DESCRIPTION        = "The route targets the route carries, as a JSON string array e.g. [\"65001:100\"]."
+	ROUTE_LOCAL_PREFERENCE_DESCRIPTION       = "The local preference of the route before the policy is applied."
+	BUILD_ROUTE_POLICY_TOOL_NAME             = "build_routepolicy"
+	ROUTE_POLICY_INTENT_DESCRIPTION          = "What the Route Policy should do, as a JSON object. Each statement permits, denies or continues with the routes matching its prefixes, communities or route targets, and can tag them with communities, route targets and a local preference."
+	ROUTE_POLICY_FABRIC_ID_DESCRIPTION       = "The Network Fabric the Route Policy is for, as an ARM ID, a name in the resource group or resourceGroup/name. Defaults to the fabric named by fabricName in the resource group."
 
 	CREATE_L3_ISOLATION_DOMAIN_TOOL_NAME                   = "create_l3isolationdomain"
 	L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION              = "The name of the L3 Isolation Domain to be created."
-	L3_ISOLATION_DOMAIN_LOCATION_DESCRIPTION               = "The location of the L3 Isolation Domain."
-	L3_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION             = "The properties of the L3 Isolation Domain. This should be a JSON string."
-	L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION         = "The name of the resource group."
-	L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION        = "The subscription ID for the Azure account."
+	L3_ISOLATION_DOMAIN_LOCATION_DESCRIPTION               = "The location of the L3 Isolation Domain. Defaults to the location of the session context."
+	L3_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION             = "The properties of the L3 Isolation Domain. This should be a JSON object." + REFERENCE_PROPERTIES_DESCRIPTION
+	L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION         = "The name of the resource group. Defaults to the resource group of the session context."
+	L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION        = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	GET_L3_ISOLATION_DOMAIN_TOOL_NAME                      = "get_l3isolationdomain"
 	GET_L3_ISOLATION_DOMAIN_ADMINISTRATIVE_STATE_TOOL_NAME = "get_l3isolationdomain_administrative_state"
 	GET_L3_ISOLATION_DOMAIN_CONFIGURATION_STATE_TOOL_NAME  = "get_l3isolationdomain_configuration_state"
@@ -58,9 +67,9 @@ const (
 
 	CREATE_INTERNAL_NETWORK_TOOL_NAME            = "create_internalnetwork"
 	INTERNAL_NETWORK_PARAMETER_DESCRIPTION       = "The name of the Internal Network to be created."
-	INTERNAL_NETWORK_PROPERTIES_DESCRIPTION      = "The properties of the Internal Network. This should be a JSON string."
-	INTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	INTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	INTERNAL_NETWORK_PROPERTIES_DESCRIPTION      = "The properties of the Internal Network. This should be a JSON object." + REFERENCE_PROPERTIES_DESCRIPTION
+	INTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	INTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	PATCH_INTERNAL_NETWORK_TOOL_NAME             = "patch_internalnetwork"
 	GET_INTERNAL_NETWORK_TOOL_NAME               = "get_internalnetwork"
 
@@ -68,10 +77,10 @@ const (
 
 	CREATE_L2_ISOLATION_DOMAIN_TOOL_NAME                   = "create_l2isolationdomain"
 	L2_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION              = "The name of the L2 Isolation Domain to be created."
-	L2_ISOLATION_DOMAIN_LOCATION_DESCRIPTION               = "The location of the L2 Isolation Domain."
-	L2_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION             = "The properties of the L2 Isolation Domain. This should be a JSON string."
-	L2_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION         = "The name of the resource group."
-	L2_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION        = "The subscription ID for the Azure account."
+	L2_ISOLATION_DOMAIN_LOCATION_DESCRIPTION               = "The location of the L2 Isolation Domain. Defaults to the location of the session context."
+	L2_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION             = "The properties of the L2 Isolation Domain. This should be a JSON object." + REFERENCE_PROPERTIES_DESCRIPTION
+	L2_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION         = "The name of the resource group. Defaults to the resource group of the session context."
+	L2_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION        = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	ENABLE_L2_ISOLATION_DOMAIN_TOOL_NAME                   = "enable_l2isolationdomain"
 	DISABLE_L2_ISOLATION_DOMAIN_TOOL_NAME                  = "disable_l2isolationdomain"
 	GET_L2_ISOLATION_DOMAIN_TOOL_NAME                      = "get_l2isolationdomain"
@@ -82,16 +91,16 @@ const (
 
 	CREATE_EXTERNAL_NETWORK_TOOL_NAME            = "create_externalnetwork"
 	EXTERNAL_NETWORK_PARAMETER_DESCRIPTION       = "The name of the External Network to be created."
-	EXTERNAL_NETWORK_PROPERTIES_DESCRIPTION      = "The properties of the External Network. This should be a JSON string."
-	EXTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	EXTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	EXTERNAL_NETWORK_PROPERTIES_DESCRIPTION      = "The properties of the External Network. This should be a JSON object." + REFERENCE_PROPERTIES_DESCRIPTION
+	EXTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	EXTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	PATCH_EXTERNAL_NETWORK_TOOL_NAME             = "patch_externalnetwork"
 	GET_EXTERNAL_NETWORK_TOOL_NAME               = "get_externalnetwork"
 
 	COMMIT_NETWORK_FABRIC_TOOL_NAME            = "commit_network_fabric"
-	NETWORK_FABRIC_PARAMETER_DESCRIPTION       = "The name of the Network Fabric."
-	NETWORK_FABRIC_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	NETWORK_FABRIC_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	NETWORK_FABRIC_PARAMETER_DESCRIPTION       = "The name of the Network Fabric. Defaults to the fabric of the session context."
+	NETWORK_FABRIC_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	NETWORK_FABRIC_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 	GET_NETWORK_FABRIC_TOOL_NAME               = "get_network_fabric"
 	LIST_DEVICES_NETWORK_FABRIC_TOOL_NAME      = "list_devices_network_fabric"
 
@@ -99,15 +108,100 @@ const (
 	DELETE_RESOURCE_GROUP_TOOL_NAME            = "delete_resourcegroup"
 	GET_RESOURCE_GROUP_TOOL_NAME               = "get_resourcegroup"
 	LIST_RESOURCES_IN_RG_TOOL_NAME             = "list_resources_in_rg"
+	LIST_RESOURCE_GROUPS_TOOL_NAME             = "list_resourcegroups"
+	RESOURCE_GROUP_LOCATION_FILTER_DESCRIPTION = "Only list resource groups in this Azure region, e.g. eastus."
+	RESOURCE_GROUP_TAG_FILTER_DESCRIPTION      = "Only list resource groups carrying all of these tags, as a JSON object of tag names to values. An empty value matches any value of the tag."
 	RESOURCE_GROUP_PARAMETER_DESCRIPTION       = "The name of the Resource Group."
-	RESOURCE_GROUP_LOCATION_DESCRIPTION        = "The location of the Resource Group."
-	RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	RESOURCE_GROUP_LOCATION_DESCRIPTION        = "The location of the Resource Group. Defaults to the location of the session context."
+	RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 
 	GET_NETWORK_DEVICE_TOOL_NAME               = "get_network_device"
 	REBOOT_NETWORK_DEVICE_TOOL_NAME            = "reboot_network_device"
 	NETWORK_DEVICE_PARAMETER_DESCRIPTION       = "The name of the Network Device."
-	NETWORK_DEVICE_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group."
-	NETWORK_DEVICE_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account."
+	NETWORK_DEVICE_RESOURCE_GROUP_DESCRIPTION  = "The name of the resource group. Defaults to the resource group of the session context."
+	NETWORK_DEVICE_SUBSCRIPTION_ID_DESCRIPTION = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
 
 	GET_LAB_STATUS_TOOL_NAME = "get_lab_status"
+
+	LIST_SUBSCRIPTIONS_TOOL_NAME      = "list_subscriptions"
+	FIND_NEXUS_RESOURCES_TOOL_NAME    = "find_nexus_resources"
+	FIND_NAME_DESCRIPTION             = "Find resources whose name contains this text, ignoring case."
+	FIND_RESOURCE_TYPE_DESCRIPTION    = "Find resources of this type, either the full ARM type e.g. Microsoft.ManagedNetworkFabric/networkFabrics or only the last segment e.g. networkFabrics."
+	FIND_SUBSCRIPTION_IDS_DESCRIPTION = "The subscriptions to search. Defaults to every subscription the credential can access."
+
+	QUERY_RESOURCE_GRAPH_TOOL_NAME              = "query_resource_graph"
+	RESOURCE_GRAPH_QUERY_NAME_DESCRIPTION       = "The name of a canned query to run."
+	RESOURCE_GRAPH_QUERY_DESCRIPTION            = "A KQL pipeline fragment applied to the Managed Network Fabric resources, or to the result of the canned query, e.g. where type =~ 'microsoft.managednetworkfabric/networkfabrics' | project name, resourceGroup, properties.configurationState. join, union and lookup are not allowed."
+	RESOURCE_GRAPH_SUBSCRIPTION_IDS_DESCRIPTION = "The subscriptions to query. Defaults to every subscription the credential can access."
+
+	SET_CONTEXT_TOOL_NAME               = "set_context"
+	NEXUS_URI_SCHEME                    = "nexus://"
+	CONTEXT_RESOURCE_URI                = NEXUS_URI_SCHEME + "context"
+	CONTEXT_SUBSCRIPTION_ID_DESCRIPTION = "The default subscription ID."
+	CONTEXT_RESOURCE_GROUP_DESCRIPTION  = "The default resource group name."
+	CONTEXT_LOCATION_DESCRIPTION        = "The default Azure region, e.g. eastus."
+	CONTEXT_FABRIC_DESCRIPTION          = "The default network fabric name."
+	CONTEXT_RESET_DESCRIPTION           = "When true, go back to the server defaults before applying the other arguments."
+
+	BRING_UP_L3_ISOLATION_DOMAIN_PROMPT_NAME = "bring_up_l3isolationdomain"
+	CREATE_ROUTE_POLICY_PROMPT_NAME          = "create_routepolicy_from_prefixes"
+	TRIAGE_LAB_PROMPT_NAME                   = "triage_unhealthy_lab"
+	TEAR_DOWN_RESOURCE_GROUP_PROMPT_NAME     = "tear_down_demo_resourcegroup"
+	PROMPT_RESOURCE_GROUP_DESCRIPTION        = "The name of the resource group. Defaults to the resource group of the session context."
+	PROMPT_SUBSCRIPTION_ID_DESCRIPTION       = "The subscription ID for the Azure account. Defaults to the subscription of the session context."
+)
+
+const (
+	NETWORK_FABRIC_PROVIDER = "Microsoft.ManagedNetworkFabric"
+	// Resource Graph reports types in lower case.
+	RESOURCE_GRAPH_TYPE_PREFIX = "microsoft.managednetworkfabric/"
+	RESOURCE_GRAPH_PAGE_SIZE   = 1000
+	MAX_RESOURCE_GRAPH_ROWS    = 5000
+
+	RESOURCE_GROUP_RESOURCE_TYPE      = "Microsoft.Resources/resourceGroups"
+	IP_PREFIX_RESOURCE_TYPE           = "Microsoft.ManagedNetworkFabric/ipPrefixes"
+	IP_COMMUNITY_RESOURCE_TYPE        = "Microsoft.ManagedNetworkFabric/ipCommunities"
+	IP_EXT_COMMUNITY_RESOURCE_TYPE    = "Microsoft.ManagedNetworkFabric/ipExtendedCommunities"
+	ROUTE_POLICY_RESOURCE_TYPE        = "Microsoft.ManagedNetworkFabric/routePolicies"
+	ACCESS_CONTROL_LIST_RESOURCE_TYPE = "Microsoft.ManagedNetworkFabric/accessControlLists"
+	L2_ISOLATION_DOMAIN_RESOURCE_TYPE = "Microsoft.ManagedNetworkFabric/l2IsolationDomains"
+	L3_ISOLATION_DOMAIN_RESOURCE_TYPE = "Microsoft.ManagedNetworkFabric/l3IsolationDomains"
+	INTERNAL_NETWORK_RESOURCE_TYPE    = "Microsoft.ManagedNetworkFabric/l3IsolationDomains/internalNetworks"
+	EXTERNAL_NETWORK_RESOURCE_TYPE    = "Microsoft.ManagedNetworkFabric/l3IsolationDomains/externalNetworks"
+	NETWORK_FABRIC_RESOURCE_TYPE      = "Microsoft.ManagedNetworkFabric/networkFabrics"
+	NETWORK_DEVICE_RESOURCE_TYPE      = "Microsoft.ManagedNetworkFabric/networkDevices"
+
+	// API version of Microsoft.ManagedNetworkFabric used by armmanagednetworkfabric, for generic ARM calls.
+	NETWORK_FABRIC_API_VERSION = "2023-06-15"
+	RESOURCE_GROUP_API_VERSION = "2021-04-01"
+
+	// Appended to the properties descriptions of the tools whose properties reference other resources.
+	REFERENCE_PROPERTIES_DESCRIPTION = " The references networkFabricId, importRoutePolicyId, exportRoutePolicyId, importIpv4RoutePolicyId, importIpv6RoutePolicyId, exportIpv4RoutePolicyId, exportIpv6RoutePolicyId, ingressAclId, egressAclId, ipPrefixId, ipCommunityIds and ipExtendedCommunityIds accept a full ARM ID, a name in the same resource group or resourceGroup/name, and must exist in the same network fabric."
+	DRY_RUN_DESCRIPTION              = "When true, validate the arguments, resolve referenced resources and return the planned change without modifying anything."
+
+	OPERATION_CREATE  = "create"
+	OPERATION_UPDATE  = "update"
+	OPERATION_DELETE  = "delete"
+	OPERATION_ENABLE  = "enable"
+	OPERATION_DISABLE = "disable"
+	OPERATION_COMMIT  = "commit"
+	OPERATION_REBOOT  = "reboot"
+)
+
+const (
+	RESOURCE_GROUP_CATEGORY      = "resourcegroup"
+	IP_PREFIX_CATEGORY           = "ipprefix"
+	IP_COMMUNITY_CATEGORY        = "ipcommunity"
+	IP_EXT_COMMUNITY_CATEGORY    = "ipextcommunity"
+	ROUTE_POLICY_CATEGORY        = "routepolicy"
+	L2_ISOLATION_DOMAIN_CATEGORY = "l2isolationdomain"
+	L3_ISOLATION_DOMAIN_CATEGORY = "l3isolationdomain"
+	INTERNAL_NETWORK_CATEGORY    = "internalnetwork"
+	EXTERNAL_NETWORK_CATEGORY    = "externalnetwork"
+	NETWORK_FABRIC_CATEGORY      = "networkfabric"
+	NETWORK_DEVICE_CATEGORY      = "networkdevice"
+	LAB_CATEGORY                 = "lab"
+	CONTEXT_CATEGORY             = "context"
+	SUBSCRIPTION_CATEGORY        = "subscription"
+	RESOURCE_GRAPH_CATEGORY      = "resourcegraph"
 )
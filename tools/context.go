@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SessionContext holds the defaults used for the subscriptionId, resourceGroupName, location and
+// fabricName arguments when a tool call leaves them out.
+type SessionContext struct {
+	SubscriptionID string `json:"subscriptionId,omitempty" jsonschema:"The default subscription ID."`
+	ResourceGroup  string `json:"resourceGroupName,omitempty" jsonschema:"The default resource group."`
+	Location       string `json:"location,omitempty" jsonschema:"The default Azure region."`
+	Fabric         string `json:"fabricName,omitempty" jsonschema:"The default network fabric."`
+}
+
+// Environment variables that provide the server wide defaults.
+var contextEnvironment = map[string]func(*SessionContext) *string{
+	"AZURE_SUBSCRIPTION_ID": func(c *SessionContext) *string { return &c.SubscriptionID },
+	"NEXUS_RESOURCE_GROUP":  func(c *SessionContext) *string { return &c.ResourceGroup },
+	"NEXUS_LOCATION":        func(c *SessionContext) *string { return &c.Location },
+	"NEXUS_FABRIC":          func(c *SessionContext) *string { return &c.Fabric },
+}
+
+// ContextStore keeps the context of every MCP session, starting from the server wide defaults.
+type ContextStore struct {
+	mu       sync.RWMutex
+	defaults SessionContext
+	sessions map[string]SessionContext
+}
+
+// NewContextStore returns a store whose defaults are taken from the configuration, overridden by
+// the environment.
+func NewContextStore(defaults SessionContext) *ContextStore {
+	for variable, field := range contextEnvironment {
+		if value := os.Getenv(variable); value != "" {
+			*field(&defaults) = value
+		}
+	}
+
+	return &ContextStore{
+		defaults: defaults,
+		sessions: make(map[string]SessionContext),
+	}
+}
+
+func (store *ContextStore) Get(ctx context.Context) SessionContext {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	if sessionContext, ok := store.sessions[sessionID(ctx)]; ok {
+		return sessionContext
+	}
+	return store.defaults
+}
+
+func (store *ContextStore) set(ctx context.Context, sessionContext SessionContext) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.sessions[sessionID(ctx)] = sessionContext
+}
+
+func (store *ContextStore) reset(ctx context.Context) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.sessions, sessionID(ctx))
+}
+
+// Forget drops the context of a session that has ended.
+func (store *ContextStore) Forget(session server.ClientSession) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.sessions, session.SessionID())
+}
+
+// Middleware fills the subscriptionId, resourceGroupName, location and fabricName arguments that a
+// tool call leaves out from the session context. set_context is left alone, as it must only apply
+// the arguments the caller sent.
+func (store *ContextStore) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Name == SET_CONTEXT_TOOL_NAME {
+				return next(ctx, request)
+			}
+
+			args, ok := request.Params.Arguments.(map[string]any)
+			if !ok {
+				if request.Params.Arguments != nil {
+					return next(ctx, request)
+				}
+				args = map[string]any{}
+			}
+
+			sessionContext := store.Get(ctx)
+			filled := make(map[string]any, len(args)+4)
+			for key, value := range args {
+				filled[key] = value
+			}
+			for key, value := range map[string]string{
+				"subscriptionId":    sessionContext.SubscriptionID,
+				"resourceGroupName": sessionContext.ResourceGroup,
+				"location":          sessionContext.Location,
+				"fabricName":        sessionContext.Fabric,
+			} {
+				if current, _ := filled[key].(string); current == "" && value != "" {
+					filled[key] = value
+				}
+			}
+
+			request.Params.Arguments = filled
+			return next(ctx, request)
+		}
+	}
+}
+
+func SetContext(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return setContext(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		store := clientRetriever.Context
+		if store == nil {
+			return nil, errors.New("session context is not enabled")
+		}
+
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		if reset, _ := args["reset"].(bool); reset {
+			store.reset(ctx)
+		}
+
+		sessionContext := store.Get(ctx)
+		for key, field := range map[string]*string{
+			"subscriptionId":    &sessionContext.SubscriptionID,
+			"resourceGroupName": &sessionContext.ResourceGroup,
+			"location":          &sessionContext.Location,
+			"fabricName":        &sessionContext.Fabric,
+		} {
+			if value, ok := args[key].(string); ok && value != "" {
+				*field = value
+			}
+		}
+		store.set(ctx, sessionContext)
+
+		resultString := "Session context updated:\n"
+		resultString += fmt.Sprintf("- Subscription ID: %s\n", sessionContext.SubscriptionID)
+		resultString += fmt.Sprintf("- Resource Group: %s\n", sessionContext.ResourceGroup)
+		resultString += fmt.Sprintf("- Location: %s\n", sessionContext.Location)
+		resultString += fmt.Sprintf("- Fabric: %s\n", sessionContext.Fabric)
+
+		return mcp.NewToolResultStructured(sessionContext, resultString), nil
+	}
+}
+
+func setContext() mcp.Tool {
+	return mcp.NewTool(
+		SET_CONTEXT_TOOL_NAME,
+		mcp.WithString("subscriptionId",
+			mcp.Description(CONTEXT_SUBSCRIPTION_ID_DESCRIPTION),
+		),
+		mcp.WithString("resourceGroupName",
+			mcp.Description(CONTEXT_RESOURCE_GROUP_DESCRIPTION),
+		),
+		mcp.WithString("location",
+			mcp.Description(CONTEXT_LOCATION_DESCRIPTION),
+		),
+		mcp.WithString("fabricName",
+			mcp.Description(CONTEXT_FABRIC_DESCRIPTION),
+		),
+		mcp.WithBoolean("reset",
+			mcp.Description(CONTEXT_RESET_DESCRIPTION),
+		),
+		// it only changes the defaults of this session, never anything in Azure
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[SessionContext](),
+		mcp.WithDescription("Set the default subscription, resource group, location and fabric used by the other tools when those arguments are left out."),
+	)
+}
+
+func ContextResource(clientRetriever ServiceClientRetriever) (mcp.Resource, server.ResourceHandlerFunc) {
+	return contextResource(), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		store := clientRetriever.Context
+		if store == nil {
+			return nil, errors.New("session context is not enabled")
+		}
+
+		contextJson, err := json.Marshal(store.Get(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal session context: %v", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(contextJson),
+			},
+		}, nil
+	}
+}
+
+func contextResource() mcp.Resource {
+	return mcp.NewResource(
+		CONTEXT_RESOURCE_URI,
+		"Session context",
+		mcp.WithResourceDescription("The default subscription, resource group, location and fabric of this session, as set with "+SET_CONTEXT_TOOL_NAME+"."),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PlannedChange is what a mutating tool would do, returned instead of calling ARM when dryRun is set.
+type PlannedChange struct {
+	ResourceID string           `json:"resourceId" jsonschema:"The ARM ID of the target resource."`
+	Exists     bool             `json:"exists" jsonschema:"Whether the target resource exists today."`
+	Current    *ResourceSummary `json:"current,omitempty" jsonschema:"The current state of the target resource."`
+	Properties map[string]any   `json:"properties,omitempty" jsonschema:"The properties that would be sent to ARM."`
+	Changes    []PropertyChange `json:"changes,omitempty" jsonschema:"The properties that would change, with their values before and after."`
+	References []ReferenceCheck `json:"references,omitempty" jsonschema:"The ARM IDs referenced by the properties and whether they exist."`
+	Warnings   []string         `json:"warnings,omitempty" jsonschema:"Problems that would likely make the operation fail or have unexpected effects."`
+}
+
+type PropertyChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type ReferenceCheck struct {
+	Property string `json:"property"`
+	ID       string `json:"id"`
+	Exists   bool   `json:"exists"`
+}
+
+// planChange resolves everything a mutating operation depends on and returns the planned change
+// without modifying any resource. proposed holds the properties that would be sent, if any.
+func planChange(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, operation string, target TargetResource, proposed any) (*mcp.CallToolResult, error) {
+	client, err := armresources.NewClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resources client: %v", err)
+	}
+
+	plan := PlannedChange{
+		ResourceID: target.ID(),
+	}
+
+	if proposed != nil {
+		proposedJson, err := json.Marshal(proposed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal properties: %v", err)
+		}
+		if err := json.Unmarshal(proposedJson, &plan.Properties); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal properties: %v", err)
+		}
+	}
+
+	current, exists, err := getGenericResource(ctx, client, plan.ResourceID)
+	if err != nil {
+		return armErrorResult("failed to get the current state of the resource", err)
+	}
+	plan.Exists = exists
+	if exists {
+		summary, err := newResourceSummary(current)
+		if err != nil {
+			return nil, err
+		}
+		plan.Current = &summary
+	}
+
+	if target.ParentName != "" {
+		if _, parentExists, err := getGenericResource(ctx, client, target.ParentID()); err != nil {
+			return armErrorResult("failed to get the parent L3 isolation domain", err)
+		} else if !parentExists {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("The parent L3 isolation domain '%s' does not exist.", target.ParentName))
+		}
+	}
+
+	switch operation {
+	case OPERATION_CREATE:
+		if exists {
+			plan.Warnings = append(plan.Warnings, "The resource already exists and would be replaced.")
+			plan.Changes = diffProperties("", plan.Current.Properties, plan.Properties)
+		}
+	case OPERATION_UPDATE:
+		if exists {
+			plan.Changes = diffProperties("", plan.Current.Properties, plan.Properties)
+			if len(plan.Changes) == 0 {
+				plan.Warnings = append(plan.Warnings, "The update does not change any property.")
+			}
+		}
+	case OPERATION_ENABLE, OPERATION_DISABLE:
+		if exists {
+			state := "Enabled"
+			if operation == OPERATION_DISABLE {
+				state = "Disabled"
+			}
+			if plan.Current.AdministrativeState == state {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("The resource is already %s.", strings.ToLower(state)))
+			} else {
+				plan.Changes = []PropertyChange{{Path: "administrativeState", Before: plan.Current.AdministrativeState, After: state}}
+			}
+		}
+	}
+
+	if !exists && operation != OPERATION_CREATE {
+		plan.Warnings = append(plan.Warnings, "The resource does not exist, so the operation would fail.")
+	}
+
+	for _, reference := range collectReferences("", plan.Properties) {
+		_, referenceExists, err := getGenericResource(ctx, client, reference.ID)
+		if err != nil {
+			return armErrorResult(fmt.Sprintf("failed to resolve %s", reference.Property), err)
+		}
+		reference.Exists = referenceExists
+		if !referenceExists {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("The resource referenced by %s does not exist: %s", reference.Property, reference.ID))
+		}
+		plan.References = append(plan.References, reference)
+	}
+
+	message := fmt.Sprintf("Dry run: would %s %s '%s'. No changes were made.", operation, target.ResourceType, target.Name)
+	return mcp.NewToolResultStructured(OperationResult{
+		Operation:     operation,
+		ResourceType:  target.ResourceType,
+		Name:          target.Name,
+		ResourceGroup: target.ResourceGroup,
+		Message:       message,
+		DryRun:        true,
+		Plan:          &plan,
+	}, message+"\n"+plan.String()), nil
+}
+
+func (plan PlannedChange) String() string {
+	result := fmt.Sprintf("- Resource ID: %s\n", plan.ResourceID)
+	result += fmt.Sprintf("- Exists: %t\n", plan.Exists)
+	if plan.Current != nil {
+		if plan.Current.ProvisioningState != "" {
+			result += fmt.Sprintf("- Provisioning State: %s\n", plan.Current.ProvisioningState)
+		}
+		if plan.Current.AdministrativeState != "" {
+			result += fmt.Sprintf("- Administrative State: %s\n", plan.Current.AdministrativeState)
+		}
+		if plan.Current.ConfigurationState != "" {
+			result += fmt.Sprintf("- Configuration State: %s\n", plan.Current.ConfigurationState)
+		}
+	}
+
+	if len(plan.Changes) > 0 {
+		result += "Changes:\n"
+		for _, change := range plan.Changes {
+			result += fmt.Sprintf("- %s: %s -> %s\n", change.Path, formatPlanValue(change.Before), formatPlanValue(change.After))
+		}
+	}
+	if len(plan.References) > 0 {
+		result += "References:\n"
+		for _, reference := range plan.References {
+			status := "found"
+			if !reference.Exists {
+				status = "NOT FOUND"
+			}
+			result += fmt.Sprintf("- %s: %s (%s)\n", reference.Property, reference.ID, status)
+		}
+	}
+	if len(plan.Warnings) > 0 {
+		result += "Warnings:\n"
+		for _, warning := range plan.Warnings {
+			result += fmt.Sprintf("- %s\n", warning)
+		}
+	}
+
+	return result
+}
+
+// getGenericResource gets a resource group or Nexus resource by ID. A resource that does not exist
+// is not an error.
+func getGenericResource(ctx context.Context, client *armresources.Client, resourceID string) (armresources.GenericResource, bool, error) {
+	apiVersion := NETWORK_FABRIC_API_VERSION
+	if strings.Count(strings.Trim(resourceID, "/"), "/") == 3 {
+		// resource group IDs carry no provider
+		apiVersion = RESOURCE_GROUP_API_VERSION
+	}
+
+	res, err := client.GetByID(ctx, resourceID, apiVersion, nil)
+	if err != nil {
+		var respErr *azcore.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			return armresources.GenericResource{}, false, nil
+		}
+		return armresources.GenericResource{}, false, err
+	}
+	return res.GenericResource, true, nil
+}
+
+// diffProperties lists the proposed values that differ from the current ones, descending into objects.
+func diffProperties(prefix string, current, proposed map[string]any) []PropertyChange {
+	keys := make([]string, 0, len(proposed))
+	for key := range proposed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []PropertyChange
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		before := current[key]
+		after := proposed[key]
+
+		beforeMap, beforeIsMap := before.(map[string]any)
+		afterMap, afterIsMap := after.(map[string]any)
+		if beforeIsMap && afterIsMap {
+			changes = append(changes, diffProperties(path, beforeMap, afterMap)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, PropertyChange{Path: path, Before: before, After: after})
+		}
+	}
+	return changes
+}
+
+// collectReferences finds the Nexus ARM IDs in properties such as networkFabricId and ipCommunityIds.
+func collectReferences(path string, value any) []ReferenceCheck {
+	var references []ReferenceCheck
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			references = append(references, collectReferences(childPath, v[key])...)
+		}
+	case []any:
+		for i, child := range v {
+			references = append(references, collectReferences(fmt.Sprintf("%s[%d]", path, i), child)...)
+		}
+	case string:
+		lowerPath := strings.ToLower(path)
+		isIDProperty := strings.HasSuffix(lowerPath, "id") || strings.Contains(lowerPath, "ids[")
+		if isIDProperty && strings.Contains(strings.ToLower(v), "/providers/microsoft.managednetworkfabric/") {
+			references = append(references, ReferenceCheck{Property: path, ID: v})
+		}
+	}
+
+	return references
+}
+
+func formatPlanValue(value any) string {
+	if value == nil {
+		return "(not set)"
+	}
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(valueJson)
+}
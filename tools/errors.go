@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ARMError is the decoded form of an error returned by Azure Resource Manager.
+type ARMError struct {
+	StatusCode    int
+	Code          string
+	Message       string
+	Target        string
+	Details       []ARMErrorDetail
+	CorrelationID string
+	RequestID     string
+}
+
+type ARMErrorDetail struct {
+	Code    string           `json:"code"`
+	Message string           `json:"message"`
+	Target  string           `json:"target"`
+	Details []ARMErrorDetail `json:"details"`
+}
+
+type armErrorHint struct {
+	matches func(armErr ARMError) bool
+	hint    string
+}
+
+// Hints for failures we commonly see on Nexus fabrics, checked in order.
+var armErrorHints = []armErrorHint{
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.hasCode("AuthorizationFailed", "LinkedAuthorizationFailed", "InvalidAuthenticationToken") || armErr.StatusCode == http.StatusForbidden
+		},
+		hint: "The signed in identity is not allowed to perform this action. Check the role assignments on the subscription or resource group, or run 'az login' with an account that has access.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.hasCode("MissingSubscriptionRegistration")
+		},
+		hint: "The Microsoft.ManagedNetworkFabric resource provider is not registered. Run 'az provider register --namespace Microsoft.ManagedNetworkFabric' on the subscription.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.mentions("vlan") && armErr.mentions("in use", "already", "conflict", "overlap")
+		},
+		hint: "The VLAN ID is already used by another internal network or L2 isolation domain on this fabric. Choose a VLAN ID that is not in use.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.mentions("fabric") && armErr.mentions("not provisioned", "not in provisioned", "provisioned state")
+		},
+		hint: "The network fabric is not provisioned yet. Check its state with " + GET_NETWORK_FABRIC_TOOL_NAME + " and retry once it is provisioned.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.mentions("enabled") && (armErr.mentions("isolation domain", "isolationdomain") || armErr.mentionsWord("isd"))
+		},
+		hint: "The resource belongs to an enabled isolation domain. Disable the isolation domain first, make the change, then enable it again and commit the fabric.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.hasCode("ResourceNotFound", "ResourceGroupNotFound", "ParentResourceNotFound", "SubscriptionNotFound") || armErr.StatusCode == http.StatusNotFound
+		},
+		hint: "The resource, its parent or its resource group does not exist. Check the names, the resource group and the subscription ID.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.hasCode("AnotherOperationInProgress", "Conflict") && armErr.mentions("operation", "in progress")
+		},
+		hint: "Another operation is still running on this resource. Wait for it to finish and retry.",
+	},
+	{
+		matches: func(armErr ARMError) bool {
+			return armErr.StatusCode == http.StatusTooManyRequests
+		},
+		hint: "Azure Resource Manager is throttling requests. Wait a minute before retrying.",
+	},
+}
+
+// armErrorResult turns an ARM failure into a tool error result carrying the decoded error and a
+// remediation hint. Errors that did not come from ARM are returned as Go errors, as before.
+func armErrorResult(message string, err error) (*mcp.CallToolResult, error) {
+	armErr, ok := decodeARMError(err)
+	if !ok {
+		return nil, fmt.Errorf("%s: %v", message, err)
+	}
+
+	return mcp.NewToolResultError(fmt.Sprintf("%s.\n%s", message, armErr.String())), nil
+}
+
+func decodeARMError(err error) (ARMError, bool) {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return ARMError{}, false
+	}
+
+	armErr := ARMError{
+		StatusCode: respErr.StatusCode,
+		Code:       respErr.ErrorCode,
+	}
+
+	if respErr.RawResponse == nil {
+		return armErr, true
+	}
+
+	armErr.CorrelationID = respErr.RawResponse.Header.Get("x-ms-correlation-request-id")
+	armErr.RequestID = respErr.RawResponse.Header.Get("x-ms-request-id")
+
+	body, err := runtime.Payload(respErr.RawResponse)
+	if err != nil || len(body) == 0 {
+		return armErr, true
+	}
+
+	var payload struct {
+		Error *ARMErrorDetail `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil {
+		armErr.Message = strings.TrimSpace(string(body))
+		return armErr, true
+	}
+
+	if payload.Error.Code != "" {
+		armErr.Code = payload.Error.Code
+	}
+	armErr.Message = payload.Error.Message
+	armErr.Target = payload.Error.Target
+	armErr.Details = payload.Error.Details
+
+	return armErr, true
+}
+
+// armErrorCode returns the ARM error code of a failed response, or its status code when the
+// response carries none.
+func armErrorCode(resp *http.Response) string {
+	if code := resp.Header.Get("x-ms-error-code"); code != "" {
+		return code
+	}
+
+	// Payload buffers the body, so the SDK can still read it afterwards
+	body, err := runtime.Payload(resp)
+	if err == nil && len(body) > 0 {
+		var payload struct {
+			Error *ARMErrorDetail `json:"error"`
+		}
+		if err := json.Unmarshal(body, &payload); err == nil && payload.Error != nil && payload.Error.Code != "" {
+			return payload.Error.Code
+		}
+	}
+
+	return strconv.Itoa(resp.StatusCode)
+}
+
+func (armErr ARMError) String() string {
+	result := "Azure Resource Manager returned an error:\n"
+	result += fmt.Sprintf("- Status: %d %s\n", armErr.StatusCode, http.StatusText(armErr.StatusCode))
+	if armErr.Code != "" {
+		result += fmt.Sprintf("- Code: %s\n", armErr.Code)
+	}
+	if armErr.Message != "" {
+		result += fmt.Sprintf("- Message: %s\n", armErr.Message)
+	}
+	if armErr.Target != "" {
+		result += fmt.Sprintf("- Target: %s\n", armErr.Target)
+	}
+	if len(armErr.Details) > 0 {
+		result += "- Details:\n"
+		result += formatARMErrorDetails(armErr.Details, "  ")
+	}
+	if armErr.CorrelationID != "" {
+		result += fmt.Sprintf("- Correlation ID: %s\n", armErr.CorrelationID)
+	}
+	if armErr.RequestID != "" {
+		result += fmt.Sprintf("- Request ID: %s\n", armErr.RequestID)
+	}
+
+	for _, hint := range armErrorHints {
+		if hint.matches(armErr) {
+			result += fmt.Sprintf("\nHint: %s\n", hint.hint)
+			break
+		}
+	}
+
+	return result
+}
+
+func formatARMErrorDetails(details []ARMErrorDetail, indent string) string {
+	result := ""
+	for _, detail := range details {
+		result += fmt.Sprintf("%s- [%s] %s", indent, detail.Code, detail.Message)
+		if detail.Target != "" {
+			result += fmt.Sprintf(" (target: %s)", detail.Target)
+		}
+		result += "\n"
+		result += formatARMErrorDetails(detail.Details, indent+"  ")
+	}
+	return result
+}
+
+func (armErr ARMError) hasCode(codes ...string) bool {
+	for _, code := range codes {
+		if strings.EqualFold(armErr.Code, code) {
+			return true
+		}
+		for _, detail := range armErr.Details {
+			if strings.EqualFold(detail.Code, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mentions reports whether any of the keywords appears in the error or detail messages.
+func (armErr ARMError) mentions(keywords ...string) bool {
+	text := armErr.messages()
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsWord is mentions for whole words, for abbreviations such as isd that are also part of
+// unrelated words like isDefault or isDisabled.
+func (armErr ARMError) mentionsWord(words ...string) bool {
+	fields := strings.FieldsFunc(armErr.messages(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if slices.Contains(fields, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// messages returns the error and detail messages in lower case.
+func (armErr ARMError) messages() string {
+	text := strings.ToLower(armErr.Message)
+	for _, detail := range armErr.Details {
+		text += " " + strings.ToLower(detail.Message)
+	}
+	return text
+}
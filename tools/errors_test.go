@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestARMErrorHints(t *testing.T) {
+	isolationDomainHint := "Disable the isolation domain first"
+	tests := []struct {
+		name string
+		err  ARMError
+		hint string
+	}{
+		{
+			name: "isd abbreviation",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Message: "Cannot update the internal network while its ISD is enabled."},
+			hint: isolationDomainHint,
+		},
+		{
+			name: "isolation domain resource",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Message: "The L3IsolationDomain 'l3isd1' is Enabled, the update is not allowed."},
+			hint: isolationDomainHint,
+		},
+		{
+			name: "isolation domain in a detail",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Message: "Validation failed.", Details: []ARMErrorDetail{{Message: "Isolation domain is enabled."}}},
+			hint: isolationDomainHint,
+		},
+		{
+			name: "isd within isDefault",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Message: "Property isDefault cannot be set while the peering is enabled."},
+		},
+		{
+			name: "isd within isDisabled",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Details: []ARMErrorDetail{{Message: "isDisabled must be false while the port is enabled."}}},
+		},
+		{
+			name: "vlan in use",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Message: "VlanId 501 is already in use."},
+			hint: "The VLAN ID is already used",
+		},
+		{
+			name: "forbidden",
+			err:  ARMError{StatusCode: http.StatusForbidden, Code: "AuthorizationFailed"},
+			hint: "is not allowed to perform this action",
+		},
+		{
+			name: "not found",
+			err:  ARMError{StatusCode: http.StatusNotFound, Code: "ResourceNotFound"},
+			hint: "does not exist",
+		},
+		{
+			name: "no hint",
+			err:  ARMError{StatusCode: http.StatusBadRequest, Code: "InvalidParameter", Message: "mtu must be between 64 and 9200."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := test.err.String()
+			_, hint, found := strings.Cut(text, "\nHint: ")
+			if test.hint == "" {
+				if found {
+					t.Errorf("hint = %q, want none", hint)
+				}
+				return
+			}
+			if !strings.Contains(hint, test.hint) {
+				t.Errorf("hint = %q, want one with %q", hint, test.hint)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,9 @@ func CreateExternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, se
 			return nil, errors.New("external network name missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.ExternalNetworkProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,25 +47,45 @@ func CreateExternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, se
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   EXTERNAL_NETWORK_RESOURCE_TYPE,
+			Name:           externalNetworkName,
+			ParentName:     l3IsolationDomainName,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &properties); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create external networks client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, EXTERNAL_NETWORK_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, l3IsolationDomainName, externalNetworkName, armmanagednetworkfabric.ExternalNetwork{
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating external network: %v", err)
+			return armErrorResult("failed to begin creating external network", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create external network: %v", err)
+			return armErrorResult("failed to create external network", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("External Network '%s' created successfully in resource group '%s'", externalNetworkName, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, EXTERNAL_NETWORK_RESOURCE_TYPE, externalNetworkName, resourceGroupName, fmt.Sprintf("External Network '%s' created successfully in resource group '%s'", externalNetworkName, resourceGroupName), res)
 	}
 }
 
@@ -86,18 +100,17 @@ func createExternalNetwork() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(EXTERNAL_NETWORK_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.ExternalNetworkProperties](EXTERNAL_NETWORK_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new External Network"),
 	)
 }
@@ -129,14 +142,9 @@ func PatchExternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var patchProps armmanagednetworkfabric.ExternalNetworkPatchProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
 		}
 		properties := armmanagednetworkfabric.ExternalNetworkPatch{
 			Properties: &patchProps,
@@ -147,22 +155,42 @@ func PatchExternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   EXTERNAL_NETWORK_RESOURCE_TYPE,
+			Name:           externalNetworkName,
+			ParentName:     l3IsolationDomainName,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &patchProps); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create external networks client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, EXTERNAL_NETWORK_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, l3IsolationDomainName, externalNetworkName, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating external network: %v", err)
+			return armErrorResult("failed to begin updating external network", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update external network: %v", err)
+			return armErrorResult("failed to update external network", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("External Network '%s' updated successfully in resource group '%s'", externalNetworkName, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, EXTERNAL_NETWORK_RESOURCE_TYPE, externalNetworkName, resourceGroupName, fmt.Sprintf("External Network '%s' updated successfully in resource group '%s'", externalNetworkName, resourceGroupName), res)
 	}
 }
 
@@ -177,18 +205,17 @@ func patchExternalNetwork() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the External Network. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.ExternalNetworkPatchProperties]("The properties to update on the External Network. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an External Network"),
 	)
 }
@@ -225,22 +252,17 @@ func GetExternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, serve
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create external networks client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, l3IsolationDomainName, externalNetworkName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get external network: %v", err)
+			return armErrorResult("failed to get external network", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -256,13 +278,13 @@ func getExternalNetwork() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(EXTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an External Network"),
 	)
 }
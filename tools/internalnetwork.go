@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,9 @@ func CreateInternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, se
 			return nil, errors.New("internal network name missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.InternalNetworkProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,25 +47,45 @@ func CreateInternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, se
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   INTERNAL_NETWORK_RESOURCE_TYPE,
+			Name:           internalNetworkName,
+			ParentName:     l3IsolationDomainName,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &properties); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create internal networks client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, INTERNAL_NETWORK_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, l3IsolationDomainName, internalNetworkName, armmanagednetworkfabric.InternalNetwork{
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating internal network: %v", err)
+			return armErrorResult("failed to begin creating internal network", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create internal network: %v", err)
+			return armErrorResult("failed to create internal network", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Internal Network '%s' created successfully in resource group '%s'", internalNetworkName, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, INTERNAL_NETWORK_RESOURCE_TYPE, internalNetworkName, resourceGroupName, fmt.Sprintf("Internal Network '%s' created successfully in resource group '%s'", internalNetworkName, resourceGroupName), res)
 	}
 }
 
@@ -86,18 +100,17 @@ func createInternalNetwork() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(INTERNAL_NETWORK_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.InternalNetworkProperties](INTERNAL_NETWORK_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new Internal Network"),
 	)
 }
@@ -129,14 +142,9 @@ func PatchInternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var patchProps armmanagednetworkfabric.InternalNetworkPatchProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
 		}
 		properties := armmanagednetworkfabric.InternalNetworkPatch{
 			Properties: &patchProps,
@@ -147,22 +155,42 @@ func PatchInternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   INTERNAL_NETWORK_RESOURCE_TYPE,
+			Name:           internalNetworkName,
+			ParentName:     l3IsolationDomainName,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &patchProps); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create internal networks client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, INTERNAL_NETWORK_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, l3IsolationDomainName, internalNetworkName, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating internal network: %v", err)
+			return armErrorResult("failed to begin updating internal network", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update internal network: %v", err)
+			return armErrorResult("failed to update internal network", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Internal Network '%s' updated successfully in resource group '%s'", internalNetworkName, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, INTERNAL_NETWORK_RESOURCE_TYPE, internalNetworkName, resourceGroupName, fmt.Sprintf("Internal Network '%s' updated successfully in resource group '%s'", internalNetworkName, resourceGroupName), res)
 	}
 }
 
@@ -177,18 +205,17 @@ func patchInternalNetwork() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the Internal Network. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.InternalNetworkPatchProperties]("The properties to update on the Internal Network. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an Internal Network"),
 	)
 }
@@ -225,22 +252,17 @@ func GetInternalNetwork(clientRetriever ServiceClientRetriever) (mcp.Tool, serve
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create internal networks client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, l3IsolationDomainName, internalNetworkName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get internal network: %v", err)
+			return armErrorResult("failed to get internal network", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -256,13 +278,13 @@ func getInternalNetwork() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(INTERNAL_NETWORK_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an Internal Network"),
 	)
 }
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,12 @@ func CreateIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, errors.New("location missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.IPCommunityProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
+		}
+		if violations := validateIPCommunityRules(properties.IPCommunityRules); len(violations) > 0 {
+			return validationErrorResult("IP community rules", violations), nil
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,26 +50,42 @@ func CreateIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, IP_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, name, armmanagednetworkfabric.IPCommunity{
 			Location:   &location,
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating ip community: %v", err)
+			return armErrorResult("failed to begin creating ip community", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create ip community: %v", err)
+			return armErrorResult("failed to create ip community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Community '%s' created successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, IP_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Community '%s' created successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -84,21 +97,19 @@ func createIPCommunity() mcp.Tool {
 			mcp.Description(IPCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_LOCATION_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(IPCOMMUNITY_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.IPCommunityProperties](IPCOMMUNITY_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new IP community"),
 	)
 }
@@ -130,22 +141,38 @@ func DeleteIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_DELETE, IP_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting ip community: %v", err)
+			return armErrorResult("failed to begin deleting ip community", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete ip community: %v", err)
+			return armErrorResult("failed to delete ip community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Community '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DELETE, IP_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Community '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -157,13 +184,15 @@ func deleteIPCommunity() mcp.Tool {
 			mcp.Description(IPCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete an IP Community"),
 	)
 }
@@ -190,14 +219,12 @@ func PatchIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var patchProps armmanagednetworkfabric.IPCommunityPatchableProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
+		}
+		if violations := validateIPCommunityRules(patchProps.IPCommunityRules); len(violations) > 0 {
+			return validationErrorResult("IP community rules", violations), nil
 		}
 		properties := armmanagednetworkfabric.IPCommunityPatch{
 			Properties: &patchProps,
@@ -208,22 +235,38 @@ func PatchIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, IP_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, name, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating ip community: %v", err)
+			return armErrorResult("failed to begin updating ip community", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update ip community: %v", err)
+			return armErrorResult("failed to update ip community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Community '%s' updated successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, IP_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Community '%s' updated successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -234,18 +277,17 @@ func patchIPCommunity() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the IP Community. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.IPCommunityPatchableProperties]("The properties to update on the IP Community. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an IP Community"),
 	)
 }
@@ -277,22 +319,17 @@ func GetIPCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server.To
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip communities client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ip community: %v", err)
+			return armErrorResult("failed to get ip community", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -304,13 +341,13 @@ func getIPCommunity() mcp.Tool {
 			mcp.Description(IPCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an IP Community"),
 	)
 }
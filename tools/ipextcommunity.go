@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,12 @@ func CreateIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, errors.New("location missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.IPExtendedCommunityProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
+		}
+		if violations := validateIPExtCommunityRules(properties.IPExtendedCommunityRules); len(violations) > 0 {
+			return validationErrorResult("IP extended community rules", violations), nil
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,26 +50,42 @@ func CreateIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_EXT_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip extended communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, IP_EXT_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, name, armmanagednetworkfabric.IPExtendedCommunity{
 			Location:   &location,
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating ip extended community: %v", err)
+			return armErrorResult("failed to begin creating ip extended community", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create ip extended community: %v", err)
+			return armErrorResult("failed to create ip extended community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Extended Community '%s' created successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, IP_EXT_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Extended Community '%s' created successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -84,21 +97,19 @@ func createIPExtCommunity() mcp.Tool {
 			mcp.Description(IPEXTCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_LOCATION_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(IPEXTCOMMUNITY_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.IPExtendedCommunityProperties](IPEXTCOMMUNITY_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new IP extended community"),
 	)
 }
@@ -130,22 +141,38 @@ func DeleteIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_EXT_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip extended communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_DELETE, IP_EXT_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting ip extended community: %v", err)
+			return armErrorResult("failed to begin deleting ip extended community", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete ip extended community: %v", err)
+			return armErrorResult("failed to delete ip extended community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Extended Community '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DELETE, IP_EXT_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Extended Community '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -157,13 +184,15 @@ func deleteIPExtCommunity() mcp.Tool {
 			mcp.Description(IPEXTCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete an IP Extended Community"),
 	)
 }
@@ -190,14 +219,14 @@ func PatchIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.IPExtendedCommunityPatch
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
+		}
+		if properties.Properties != nil {
+			if violations := validateIPExtCommunityRules(properties.Properties.IPExtendedCommunityRules); len(violations) > 0 {
+				return validationErrorResult("IP extended community rules", violations), nil
+			}
 		}
 
 		cred, err := clientRetriever.Get()
@@ -205,22 +234,38 @@ func PatchIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_EXT_COMMUNITY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, properties.Properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip extended communities client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, IP_EXT_COMMUNITY_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, name, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating ip extended community: %v", err)
+			return armErrorResult("failed to begin updating ip extended community", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update ip extended community: %v", err)
+			return armErrorResult("failed to update ip extended community", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Extended Community '%s' updated successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, IP_EXT_COMMUNITY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Extended Community '%s' updated successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -231,18 +276,17 @@ func patchIPExtCommunity() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the IP Extended Community. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.IPExtendedCommunityPatch]("The properties to update on the IP Extended Community. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an IP Extended Community"),
 	)
 }
@@ -274,22 +318,17 @@ func GetIPExtCommunity(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip extended communities client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ip extended community: %v", err)
+			return armErrorResult("failed to get ip extended community", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -301,13 +340,13 @@ func getIPExtCommunity() mcp.Tool {
 			mcp.Description(IPEXTCOMMUNITY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPEXTCOMMUNITY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an IP Extended Community"),
 	)
 }
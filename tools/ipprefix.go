@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,17 @@ func CreateIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.To
 			return nil, errors.New("location missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
+		var properties armmanagednetworkfabric.IPPrefixProperties
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
 		}
 
-		var properties armmanagednetworkfabric.IPPrefixProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		versions, err := ipVersions(args)
+		if err != nil {
+			return nil, err
+		}
+		if violations := validateIPPrefixRules(properties.IPPrefixRules, versions); len(violations) > 0 {
+			return validationErrorResult("IP prefix rules", violations), nil
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,26 +55,42 @@ func CreateIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.To
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_PREFIX_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip prefixes client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, IP_PREFIX_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, name, armmanagednetworkfabric.IPPrefix{
 			Location:   &location,
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating ip prefix: %v", err)
+			return armErrorResult("failed to begin creating ip prefix", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create ip prefix: %v", err)
+			return armErrorResult("failed to create ip prefix", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Prefix '%s' created successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, IP_PREFIX_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Prefix '%s' created successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -84,21 +102,23 @@ func createIPPrefix() mcp.Tool {
 			mcp.Description(IPREFIX_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(IPREFIX_LOCATION_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(IPREFIX_PROPERTIES_DESCRIPTION),
+		propertiesParameter[armmanagednetworkfabric.IPPrefixProperties](IPREFIX_PROPERTIES_DESCRIPTION),
+		mcp.WithArray("types",
+			mcp.Description(IPREFIX_IP_DESCRIPTION),
+			mcp.WithStringEnumItems([]string{IP_VERSION_IPV4, IP_VERSION_IPV6}),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPREFIX_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPREFIX_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new IP prefix"),
 	)
 }
@@ -130,22 +150,38 @@ func DeleteIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.To
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_PREFIX_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip prefixes client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_DELETE, IP_PREFIX_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting ip prefix: %v", err)
+			return armErrorResult("failed to begin deleting ip prefix", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete ip prefix: %v", err)
+			return armErrorResult("failed to delete ip prefix", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Prefix '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DELETE, IP_PREFIX_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Prefix '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -157,13 +193,15 @@ func deleteIPPrefix() mcp.Tool {
 			mcp.Description(IPREFIX_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPREFIX_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPREFIX_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete an IP Prefix"),
 	)
 }
@@ -190,14 +228,17 @@ func PatchIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Too
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
+		var patchProps armmanagednetworkfabric.IPPrefixPatchProperties
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
 		}
 
-		var patchProps armmanagednetworkfabric.IPPrefixPatchProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		versions, err := ipVersions(args)
+		if err != nil {
+			return nil, err
+		}
+		if violations := validateIPPrefixRules(patchProps.IPPrefixRules, versions); len(violations) > 0 {
+			return validationErrorResult("IP prefix rules", violations), nil
 		}
 		properties := armmanagednetworkfabric.IPPrefixPatch{
 			Properties: &patchProps,
@@ -208,22 +249,38 @@ func PatchIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Too
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   IP_PREFIX_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip prefixes client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, IP_PREFIX_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, name, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating ip prefix: %v", err)
+			return armErrorResult("failed to begin updating ip prefix", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update ip prefix: %v", err)
+			return armErrorResult("failed to update ip prefix", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("IP Prefix '%s' updated successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, IP_PREFIX_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("IP Prefix '%s' updated successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -234,18 +291,21 @@ func patchIPPrefix() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(IPREFIX_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the IP Prefix. This should be a JSON string."),
+		propertiesParameter[armmanagednetworkfabric.IPPrefixPatchProperties]("The properties to update on the IP Prefix. This should be a JSON object."),
+		mcp.WithArray("types",
+			mcp.Description(IPREFIX_IP_DESCRIPTION),
+			mcp.WithStringEnumItems([]string{IP_VERSION_IPV4, IP_VERSION_IPV6}),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPREFIX_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPREFIX_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an IP Prefix"),
 	)
 }
@@ -277,22 +337,17 @@ func GetIPPrefix(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolH
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ip prefixes client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ip prefix: %v", err)
+			return armErrorResult("failed to get ip prefix", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -304,13 +359,13 @@ func getIPPrefix() mcp.Tool {
 			mcp.Description(IPREFIX_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(IPREFIX_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(IPREFIX_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an IP Prefix"),
 	)
 }
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,9 @@ func CreateL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, errors.New("location missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.L3IsolationDomainProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,26 +47,46 @@ func CreateL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+			Name:           name,
+			FabricID:       properties.NetworkFabricID,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &properties); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, name, armmanagednetworkfabric.L3IsolationDomain{
 			Location:   &location,
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating L3 isolation domain: %v", err)
+			return armErrorResult("failed to begin creating L3 isolation domain", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create L3 isolation domain: %v", err)
+			return armErrorResult("failed to create L3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("L3 Isolation Domain '%s' created successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("L3 Isolation Domain '%s' created successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -84,21 +98,19 @@ func createL3IsolationDomain() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_LOCATION_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(L3_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.L3IsolationDomainProperties](L3_ISOLATION_DOMAIN_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new L3 Isolation Domain"),
 	)
 }
@@ -130,26 +142,42 @@ func EnableL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_ENABLE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_ENABLE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
 		state := armmanagednetworkfabric.EnableDisableState("Enable")
+		timer := startOperationTimer(ctx, OPERATION_ENABLE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
 		poller, err := client.BeginUpdateAdministrativeState(ctx, resourceGroupName, name, armmanagednetworkfabric.UpdateAdministrativeState{
 			State: &state,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin enabling L3 isolation domain: %v", err)
+			return armErrorResult("failed to begin enabling L3 isolation domain", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to enable L3 isolation domain: %v", err)
+			return armErrorResult("failed to enable L3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("L3 Isolation Domain '%s' enabled successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_ENABLE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("L3 Isolation Domain '%s' enabled successfully in resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -161,13 +189,15 @@ func enableL3IsolationDomain() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Enable an L3 Isolation Domain"),
 	)
 }
@@ -199,26 +229,42 @@ func DisableL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DISABLE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DISABLE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
 		state := armmanagednetworkfabric.EnableDisableState("Disable")
+		timer := startOperationTimer(ctx, OPERATION_DISABLE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
 		poller, err := client.BeginUpdateAdministrativeState(ctx, resourceGroupName, name, armmanagednetworkfabric.UpdateAdministrativeState{
 			State: &state,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin disabling L3 isolation domain: %v", err)
+			return armErrorResult("failed to begin disabling L3 isolation domain", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to disable L3 isolation domain: %v", err)
+			return armErrorResult("failed to disable L3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("L3 Isolation Domain '%s' disabled successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DISABLE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("L3 Isolation Domain '%s' disabled successfully in resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -230,13 +276,15 @@ func disableL3IsolationDomain() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Disable an L3 Isolation Domain"),
 	)
 }
@@ -268,22 +316,17 @@ func GetL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool, ser
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get L3 isolation domain: %v", err)
-		}
-
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
+			return armErrorResult("failed to get L3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -295,13 +338,13 @@ func getL3IsolationDomain() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get an L3 Isolation Domain"),
 	)
 }
@@ -333,21 +376,22 @@ func GetL3IsolationDomainAdministrativeState(clientRetriever ServiceClientRetrie
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get L3 isolation domain: %v", err)
+			return armErrorResult("failed to get L3 isolation domain", err)
 		}
 
 		if res.Properties == nil || res.Properties.AdministrativeState == nil {
 			return nil, fmt.Errorf("administrative state not found for L3 isolation domain '%s'", name)
 		}
 
-		return mcp.NewToolResultText(string(*res.Properties.AdministrativeState)), nil
+		state := string(*res.Properties.AdministrativeState)
+		return mcp.NewToolResultStructured(StateResult{Name: name, State: state}, state), nil
 	}
 }
 
@@ -359,13 +403,13 @@ func getL3IsolationDomainAdministrativeState() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[StateResult](),
 		mcp.WithDescription("Get the administrative state of an L3 Isolation Domain"),
 	)
 }
@@ -397,21 +441,22 @@ func GetL3IsolationDomainConfigurationState(clientRetriever ServiceClientRetriev
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get L3 isolation domain: %v", err)
+			return armErrorResult("failed to get L3 isolation domain", err)
 		}
 
 		if res.Properties == nil || res.Properties.ConfigurationState == nil {
 			return nil, fmt.Errorf("configuration state not found for L3 isolation domain '%s'", name)
 		}
 
-		return mcp.NewToolResultText(string(*res.Properties.ConfigurationState)), nil
+		state := string(*res.Properties.ConfigurationState)
+		return mcp.NewToolResultStructured(StateResult{Name: name, State: state}, state), nil
 	}
 }
 
@@ -423,13 +468,13 @@ func getL3IsolationDomainConfigurationState() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[StateResult](),
 		mcp.WithDescription("Get the configuration state of an L3 Isolation Domain"),
 	)
 }
@@ -461,22 +506,43 @@ func DeleteL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
 		}
 
+		impact := l3IsolationDomainDeleteImpact(ctx, clientRetriever, cred, subscriptionId, resourceGroupName, name)
+		if result, err := confirmAction(ctx, clientRetriever, fmt.Sprintf("Delete L3 Isolation Domain '%s' in resource group '%s'", name, resourceGroupName), impact); result != nil || err != nil {
+			return result, err
+		}
+
+		timer := startOperationTimer(ctx, OPERATION_DELETE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting L3 isolation domain: %v", err)
+			return armErrorResult("failed to begin deleting L3 isolation domain", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete L3 isolation domain: %v", err)
+			return armErrorResult("failed to delete L3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("L3 Isolation Domain '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DELETE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("L3 Isolation Domain '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -488,13 +554,15 @@ func deleteL3IsolationDomain() mcp.Tool {
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete an L3 Isolation Domain"),
 	)
 }
@@ -521,14 +589,9 @@ func PatchL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool, s
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var patchProps armmanagednetworkfabric.L3IsolationDomainPatchProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
 		}
 		properties := armmanagednetworkfabric.L3IsolationDomainPatch{
 			Properties: &patchProps,
@@ -539,22 +602,41 @@ func PatchL3IsolationDomain(clientRetriever ServiceClientRetriever) (mcp.Tool, s
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   L3_ISOLATION_DOMAIN_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &patchProps); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create l3 isolation domains client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, name, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating l3 isolation domain: %v", err)
+			return armErrorResult("failed to begin updating l3 isolation domain", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update l3 isolation domain: %v", err)
+			return armErrorResult("failed to update l3 isolation domain", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("L3 Isolation Domain '%s' updated successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, L3_ISOLATION_DOMAIN_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("L3 Isolation Domain '%s' updated successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -565,18 +647,17 @@ func patchL3IsolationDomain() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the L3 Isolation Domain. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.L3IsolationDomainPatchProperties]("The properties to update on the L3 Isolation Domain. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(L3_ISOLATION_DOMAIN_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch an L3 Isolation Domain"),
 	)
 }
@@ -11,6 +11,7 @@ import (
 )
 
 type LabStatus struct {
+	Healthy      bool           `json:"healthy"`
 	FabricStatus []FabricStatus `json:"fabricStatus"`
 }
 
@@ -51,25 +52,25 @@ func GetLabStatus(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Tool
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, nil)
+		fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
 		}
 
-		devicesClient, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, nil)
+		devicesClient, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network devices client: %v", err)
 		}
 
-		var labStatus LabStatus
+		labStatus := LabStatus{FabricStatus: []FabricStatus{}}
 		fabricPager := fabricsClient.NewListByResourceGroupPager(resourceGroupName, nil)
 		for fabricPager.More() {
 			page, err := fabricPager.NextPage(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get next page of fabrics: %v", err)
+				return armErrorResult("failed to get next page of fabrics", err)
 			}
 			for _, fabric := range page.Value {
-				var fabricStatus FabricStatus
+				fabricStatus := FabricStatus{DeviceStatus: []DeviceStatus{}}
 				fabricStatus.Name = *fabric.Name
 				fabricStatus.ProvisioningState = string(*fabric.Properties.ProvisioningState)
 				fabricStatus.AdministrativeState = string(*fabric.Properties.AdministrativeState)
@@ -77,13 +78,13 @@ func GetLabStatus(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Tool
 
 				deviceIds, err := getDeviceIdsForFabric(ctx, clientRetriever, subscriptionId, resourceGroupName, *fabric.Name)
 				if err != nil {
-					return nil, fmt.Errorf("failed to get device IDs for fabric %s: %v", *fabric.Name, err)
+					return armErrorResult(fmt.Sprintf("failed to get device IDs for fabric %s", *fabric.Name), err)
 				}
 
 				for _, deviceId := range deviceIds {
 					device, err := devicesClient.Get(ctx, resourceGroupName, deviceId, nil)
 					if err != nil {
-						return nil, fmt.Errorf("failed to get device %s: %v", deviceId, err)
+						return armErrorResult(fmt.Sprintf("failed to get device %s", deviceId), err)
 					}
 					var deviceStatus DeviceStatus
 					deviceStatus.Name = *device.Name
@@ -117,6 +118,8 @@ func GetLabStatus(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Tool
 			}
 		}
 
+		labStatus.Healthy = isHealthy
+
 		var resultString string
 		if isHealthy {
 			resultString = "The lab is in a healthy state.\n\n"
@@ -136,7 +139,7 @@ func GetLabStatus(clientRetriever ServiceClientRetriever) (mcp.Tool, server.Tool
 			resultString += "\n"
 		}
 
-		return mcp.NewToolResultText(resultString), nil
+		return mcp.NewToolResultStructured(labStatus, resultString), nil
 	}
 }
 
@@ -144,13 +147,13 @@ func getLabStatus() mcp.Tool {
 	return mcp.NewTool(
 		GET_LAB_STATUS_TOOL_NAME,
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description("The name of the resource group."),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description("The subscription ID for the Azure account."),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[LabStatus](),
 		mcp.WithDescription("Gets the status of the lab, including network fabrics and devices."),
 	)
 }
@@ -161,27 +164,27 @@ func getDeviceIdsForFabric(ctx context.Context, clientRetriever ServiceClientRet
 		return nil, fmt.Errorf("error getting credentials: %v", err)
 	}
 
-	fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, nil)
+	fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
+		return nil, fmt.Errorf("failed to create network fabrics client: %w", err)
 	}
 
 	fabric, err := fabricsClient.Get(ctx, resourceGroupName, fabricName, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get network fabric: %v", err)
+		return nil, fmt.Errorf("failed to get network fabric: %w", err)
 	}
 
 	var fabricDeviceIds []string
 	if fabric.Properties.Racks != nil {
-		racksClient, err := armmanagednetworkfabric.NewNetworkRacksClient(subscriptionId, cred, nil)
+		racksClient, err := armmanagednetworkfabric.NewNetworkRacksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
-			return nil, fmt.Errorf("failed to create network racks client: %v", err)
+			return nil, fmt.Errorf("failed to create network racks client: %w", err)
 		}
 		for _, rackId := range fabric.Properties.Racks {
 			rackName := getNameFromID(*rackId)
 			rackResp, err := racksClient.Get(ctx, resourceGroupName, rackName, nil)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get network rack %s: %v", rackName, err)
+				return nil, fmt.Errorf("failed to get network rack %s: %w", rackName, err)
 			}
 			// Check rack provisioning state for health check
 			if *rackResp.NetworkRack.Properties.ProvisioningState != armmanagednetworkfabric.ProvisioningStateSucceeded {
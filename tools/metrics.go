@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const METRICS_NAMESPACE = "nexus_mcp"
+
+// Metrics holds the Prometheus collectors of the server, fed by its middleware and pipeline policy.
+type Metrics struct {
+	toolCalls       *prometheus.CounterVec
+	toolDuration    *prometheus.HistogramVec
+	operationTime   *prometheus.HistogramVec
+	armRequests     *prometheus.CounterVec
+	armErrors       *prometheus.CounterVec
+	armThrottled    prometheus.Counter
+	armRequestTimes *prometheus.HistogramVec
+}
+
+// Buckets for tool calls and ARM requests, which range from milliseconds for a get to tens of
+// minutes for a fabric commit.
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1200, 1800}
+
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	metrics := &Metrics{
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "tool_calls_total",
+			Help:      "Tool calls by tool and outcome (success, tool_error or error).",
+		}, []string{"tool", "outcome"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of tool calls.",
+			Buckets:   durationBuckets,
+		}, []string{"tool"}),
+		operationTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of completed long running operations, from the begin call until polling finished.",
+			Buckets:   durationBuckets,
+		}, []string{"operation", "resource_type"}),
+		armRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "arm_requests_total",
+			Help:      "ARM requests by method and status code.",
+		}, []string{"method", "status_code"}),
+		armErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "arm_errors_total",
+			Help:      "Failed ARM requests by ARM error code.",
+		}, []string{"code"}),
+		armThrottled: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "arm_throttled_total",
+			Help:      "ARM requests rejected with 429 Too Many Requests.",
+		}),
+		armRequestTimes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "arm_request_duration_seconds",
+			Help:      "Duration of single ARM requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	registerer.MustRegister(
+		metrics.toolCalls,
+		metrics.toolDuration,
+		metrics.operationTime,
+		metrics.armRequests,
+		metrics.armErrors,
+		metrics.armThrottled,
+		metrics.armRequestTimes,
+	)
+
+	return metrics
+}
+
+// Middleware counts and times every tool call, and makes the metrics available to the long running
+// operations the call starts.
+func (metrics *Metrics) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx = context.WithValue(ctx, metricsKey{}, metrics)
+
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			outcome, _ := callOutcome(result, err)
+			metrics.toolCalls.WithLabelValues(request.Params.Name, outcome).Inc()
+			metrics.toolDuration.WithLabelValues(request.Params.Name).Observe(time.Since(start).Seconds())
+
+			return result, err
+		}
+	}
+}
+
+type metricsKey struct{}
+
+// operationTimer times a long running operation from its begin call until polling finished.
+type operationTimer struct {
+	metrics      *Metrics
+	operation    string
+	resourceType string
+	start        time.Time
+}
+
+// startOperationTimer is called right before the begin call of a long running operation.
+func startOperationTimer(ctx context.Context, operation, resourceType string) operationTimer {
+	metrics, _ := ctx.Value(metricsKey{}).(*Metrics)
+	return operationTimer{
+		metrics:      metrics,
+		operation:    operation,
+		resourceType: resourceType,
+		start:        time.Now(),
+	}
+}
+
+// stop is called with the result of polling, and records the duration of operations that completed.
+func (timer operationTimer) stop(err error) {
+	if timer.metrics == nil || err != nil {
+		return
+	}
+	timer.metrics.operationTime.WithLabelValues(timer.operation, timer.resourceType).Observe(time.Since(timer.start).Seconds())
+}
+
+// Policy returns the pipeline policy that counts ARM requests, errors and throttling.
+func (metrics *Metrics) Policy() policy.Policy {
+	return metricsPolicy{metrics: metrics}
+}
+
+type metricsPolicy struct {
+	metrics *Metrics
+}
+
+func (p metricsPolicy) Do(req *policy.Request) (*http.Response, error) {
+	method := req.Raw().Method
+
+	start := time.Now()
+	resp, err := req.Next()
+	p.metrics.armRequestTimes.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		p.metrics.armRequests.WithLabelValues(method, "none").Inc()
+		return resp, err
+	}
+
+	p.metrics.armRequests.WithLabelValues(method, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		p.metrics.armThrottled.Inc()
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		p.metrics.armErrors.WithLabelValues(armErrorCode(resp)).Inc()
+	}
+
+	return resp, err
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -40,22 +39,17 @@ func GetNetworkDevice(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network devices client: %v", err)
 		}
 
 		device, err := client.Get(ctx, resourceGroupName, deviceName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get network device: %v", err)
+			return armErrorResult("failed to get network device", err)
 		}
 
-		jsonResult, err := json.Marshal(device)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal network device result: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(jsonResult)), nil
+		return newResourceResult(device)
 	}
 }
 
@@ -67,13 +61,13 @@ func getNetworkDevice() mcp.Tool {
 			mcp.Description(NETWORK_DEVICE_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(NETWORK_DEVICE_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(NETWORK_DEVICE_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Gets the details of a network device."),
 	)
 }
@@ -105,34 +99,54 @@ func RebootNetworkDevice(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   NETWORK_DEVICE_RESOURCE_TYPE,
+			Name:           deviceName,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_REBOOT, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_REBOOT, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network devices client: %v", err)
 		}
 
 		device, err := client.Get(ctx, resourceGroupName, deviceName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get network device: %v", err)
+			return armErrorResult("failed to get network device", err)
 		}
 
 		if device.Properties.SerialNumber != nil && strings.Contains(*device.Properties.SerialNumber, "cEOSLab") {
-			return mcp.NewToolResultText(fmt.Sprintf("Skipping reboot for vlab device '%s'.", deviceName)), nil
+			return newOperationResult(OPERATION_REBOOT, NETWORK_DEVICE_RESOURCE_TYPE, deviceName, resourceGroupName, fmt.Sprintf("Skipping reboot for vlab device '%s'.", deviceName)), nil
+		}
+
+		if result, err := confirmAction(ctx, clientRetriever, fmt.Sprintf("Reboot Network Device '%s' in resource group '%s'", deviceName, resourceGroupName), networkDeviceRebootImpact(device.NetworkDevice)); result != nil || err != nil {
+			return result, err
 		}
 
 		rebootProperties := armmanagednetworkfabric.RebootProperties{
 			RebootType: to.Ptr(armmanagednetworkfabric.RebootType("Graceful")),
 		}
+		timer := startOperationTimer(ctx, OPERATION_REBOOT, NETWORK_DEVICE_RESOURCE_TYPE)
 		poller, err := client.BeginReboot(ctx, resourceGroupName, deviceName, rebootProperties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin reboot on network device: %v", err)
+			return armErrorResult("failed to begin reboot on network device", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to reboot network device: %v", err)
+			return armErrorResult("failed to reboot network device", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Network Device '%s' rebooted successfully.", deviceName)), nil
+		return newOperationResult(OPERATION_REBOOT, NETWORK_DEVICE_RESOURCE_TYPE, deviceName, resourceGroupName, fmt.Sprintf("Network Device '%s' rebooted successfully.", deviceName)), nil
 	}
 }
 
@@ -144,13 +158,15 @@ func rebootNetworkDevice() mcp.Tool {
 			mcp.Description(NETWORK_DEVICE_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(NETWORK_DEVICE_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(NETWORK_DEVICE_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Reboots a network device."),
 	)
 }
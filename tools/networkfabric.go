@@ -40,22 +40,43 @@ func CommitNetworkFabric(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   NETWORK_FABRIC_RESOURCE_TYPE,
+			Name:           fabricName,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_COMMIT, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_COMMIT, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
 		}
 
+		impact := networkFabricCommitImpact(ctx, clientRetriever, cred, subscriptionId, resourceGroupName, fabricName)
+		if result, err := confirmAction(ctx, clientRetriever, fmt.Sprintf("Commit configuration on Network Fabric '%s'", fabricName), impact); result != nil || err != nil {
+			return result, err
+		}
+
+		timer := startOperationTimer(ctx, OPERATION_COMMIT, NETWORK_FABRIC_RESOURCE_TYPE)
 		poller, err := client.BeginCommitConfiguration(ctx, resourceGroupName, fabricName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin commit configuration on network fabric: %v", err)
+			return armErrorResult("failed to begin commit configuration on network fabric", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to commit configuration on network fabric: %v", err)
+			return armErrorResult("failed to commit configuration on network fabric", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Network Fabric '%s' configuration has been committed.", fabricName)), nil
+		return newOperationResult(OPERATION_COMMIT, NETWORK_FABRIC_RESOURCE_TYPE, fabricName, resourceGroupName, fmt.Sprintf("Network Fabric '%s' configuration has been committed.", fabricName)), nil
 	}
 }
 
@@ -63,17 +84,18 @@ func commitNetworkFabric() mcp.Tool {
 	return mcp.NewTool(
 		COMMIT_NETWORK_FABRIC_TOOL_NAME,
 		mcp.WithString("fabricName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Commits the configuration of the network fabric."),
 	)
 }
@@ -105,21 +127,17 @@ func GetNetworkFabric(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
 		}
 
 		fabric, err := client.Get(ctx, resourceGroupName, fabricName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get network fabric: %v", err)
+			return armErrorResult("failed to get network fabric", err)
 		}
 
-		jsonResult, err := json.Marshal(fabric)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal network fabric result: %v", err)
-		}
-		return mcp.NewToolResultText(string(jsonResult)), nil
+		return newResourceResult(fabric)
 	}
 }
 
@@ -127,17 +145,16 @@ func getNetworkFabric() mcp.Tool {
 	return mcp.NewTool(
 		GET_NETWORK_FABRIC_TOOL_NAME,
 		mcp.WithString("fabricName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Gets the configuration of the network fabric."),
 	)
 }
@@ -169,19 +186,19 @@ func ListDevicesNetworkFabric(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, nil)
+		fabricsClient, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
 		}
 
 		fabric, err := fabricsClient.Get(ctx, resourceGroupName, fabricName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get network fabric: %v", err)
+			return armErrorResult("failed to get network fabric", err)
 		}
 
-		var fabricDeviceIds []string
+		fabricDeviceIds := []string{}
 		if fabric.Properties.Racks != nil {
-			racksClient, err := armmanagednetworkfabric.NewNetworkRacksClient(subscriptionId, cred, nil)
+			racksClient, err := armmanagednetworkfabric.NewNetworkRacksClient(subscriptionId, cred, clientRetriever.ClientOptions())
 			if err != nil {
 				return nil, fmt.Errorf("failed to create network racks client: %v", err)
 			}
@@ -189,7 +206,7 @@ func ListDevicesNetworkFabric(clientRetriever ServiceClientRetriever) (mcp.Tool,
 				rackName := getNameFromID(*rackId)
 				rackResp, err := racksClient.Get(ctx, resourceGroupName, rackName, nil)
 				if err != nil {
-					return nil, fmt.Errorf("failed to get network rack %s: %v", rackName, err)
+					return armErrorResult(fmt.Sprintf("failed to get network rack %s", rackName), err)
 				}
 				if rackResp.NetworkRack.Properties.NetworkDevices != nil {
 					for _, deviceId := range rackResp.NetworkRack.Properties.NetworkDevices {
@@ -204,7 +221,10 @@ func ListDevicesNetworkFabric(clientRetriever ServiceClientRetriever) (mcp.Tool,
 			return nil, fmt.Errorf("failed to marshal device IDs: %v", err)
 		}
 
-		return mcp.NewToolResultText(string(jsonResult)), nil
+		return mcp.NewToolResultStructured(NetworkDeviceList{
+			FabricName: fabricName,
+			Devices:    fabricDeviceIds,
+		}, string(jsonResult)), nil
 	}
 }
 
@@ -212,17 +232,16 @@ func listDevicesNetworkFabric() mcp.Tool {
 	return mcp.NewTool(
 		LIST_DEVICES_NETWORK_FABRIC_TOOL_NAME,
 		mcp.WithString("fabricName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(NETWORK_FABRIC_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[NetworkDeviceList](),
 		mcp.WithDescription("Gets the list of devices from a network fabric."),
 	)
 }
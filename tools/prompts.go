@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Runbook is an MCP prompt that walks the model through a workflow with the tools in the right
+// order. Tools lists every tool the runbook calls, so it is only offered when all are registered.
+type Runbook struct {
+	server.ServerPrompt
+	Tools []string
+}
+
+// Runbooks returns the prompts of the workflows operators run repeatedly.
+func Runbooks() []Runbook {
+	return []Runbook{
+		bringUpL3IsolationDomain(),
+		createRoutePolicyFromPrefixes(),
+		triageUnhealthyLab(),
+		tearDownDemoResourceGroup(),
+	}
+}
+
+func bringUpL3IsolationDomain() Runbook {
+	prompt := mcp.NewPrompt(
+		BRING_UP_L3_ISOLATION_DOMAIN_PROMPT_NAME,
+		mcp.WithPromptDescription("Bring up an L3 isolation domain with an internal network and an OptionA external network, then enable it and commit the fabric."),
+		mcp.WithArgument("fabricName", mcp.RequiredArgument(), mcp.ArgumentDescription("The network fabric the isolation domain belongs to.")),
+		mcp.WithArgument("l3IsolationDomainName", mcp.RequiredArgument(), mcp.ArgumentDescription("The name of the new L3 isolation domain.")),
+		mcp.WithArgument("internalNetworkName", mcp.ArgumentDescription("The name of the internal network.")),
+		mcp.WithArgument("internalVlanId", mcp.ArgumentDescription("The VLAN ID of the internal network.")),
+		mcp.WithArgument("internalSubnet", mcp.ArgumentDescription("The connected IPv4 subnet of the internal network, e.g. 10.0.0.0/24.")),
+		mcp.WithArgument("externalNetworkName", mcp.ArgumentDescription("The name of the OptionA external network.")),
+		mcp.WithArgument("externalVlanId", mcp.ArgumentDescription("The VLAN ID of the external network.")),
+		mcp.WithArgument("peerASN", mcp.ArgumentDescription("The ASN of the peer of the external network.")),
+		mcp.WithArgument("resourceGroupName", mcp.ArgumentDescription(PROMPT_RESOURCE_GROUP_DESCRIPTION)),
+		mcp.WithArgument("subscriptionId", mcp.ArgumentDescription(PROMPT_SUBSCRIPTION_ID_DESCRIPTION)),
+	)
+
+	handler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+		fabric := promptArgument(args, "fabricName", "")
+		isolationDomain := promptArgument(args, "l3IsolationDomainName", "")
+		if fabric == "" || isolationDomain == "" {
+			return nil, fmt.Errorf("fabricName and l3IsolationDomainName are required")
+		}
+		internalNetwork := promptArgument(args, "internalNetworkName", isolationDomain+"-internal")
+		externalNetwork := promptArgument(args, "externalNetworkName", isolationDomain+"-external")
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Bring up the L3 isolation domain '%s' on the network fabric '%s' in %s.\n\n", isolationDomain, fabric, promptScope(args))
+		text.WriteString("Follow these steps in order and stop at the first failure:\n")
+		fmt.Fprintf(&text, "1. Call %s for '%s' and check that its configuration state is Provisioned. Note its ARM ID.\n", GET_NETWORK_FABRIC_TOOL_NAME, fabric)
+		fmt.Fprintf(&text, "2. Call %s for '%s' with networkFabricId set to the fabric ID, first with dryRun true. Show the plan, then call it again without dryRun.\n", CREATE_L3_ISOLATION_DOMAIN_TOOL_NAME, isolationDomain)
+		fmt.Fprintf(&text, "3. Call %s for '%s' in '%s' with vlanId %s, mtu 1500 and connectedIPv4Subnets [{\"prefix\": \"%s\"}], first with dryRun true.\n", CREATE_INTERNAL_NETWORK_TOOL_NAME, internalNetwork, isolationDomain, promptArgument(args, "internalVlanId", "<ask the user>"), promptArgument(args, "internalSubnet", "<ask the user>"))
+		fmt.Fprintf(&text, "4. Call %s for '%s' in '%s' with peeringOption OptionA and optionAProperties {vlanId: %s, peerASN: %s, primaryIpv4Prefix, secondaryIpv4Prefix, mtu: 1500}, first with dryRun true. Ask the user for the /30 prefixes if they were not given.\n", CREATE_EXTERNAL_NETWORK_TOOL_NAME, externalNetwork, isolationDomain, promptArgument(args, "externalVlanId", "<ask the user>"), promptArgument(args, "peerASN", "<ask the user>"))
+		fmt.Fprintf(&text, "5. Call %s for '%s'.\n", ENABLE_L3_ISOLATION_DOMAIN_TOOL_NAME, isolationDomain)
+		fmt.Fprintf(&text, "6. Call %s for '%s' to apply the change to the devices. The user is asked to confirm it.\n", COMMIT_NETWORK_FABRIC_TOOL_NAME, fabric)
+		fmt.Fprintf(&text, "7. Call %s and %s for '%s' and report the states, together with the IDs of the created networks.\n", GET_L3_ISOLATION_DOMAIN_ADMINISTRATIVE_STATE_TOOL_NAME, GET_L3_ISOLATION_DOMAIN_CONFIGURATION_STATE_TOOL_NAME, isolationDomain)
+		text.WriteString("\nDo not pick VLAN IDs or prefixes yourself; ask the user for any value that is missing.")
+
+		return runbookResult(prompt, text.String()), nil
+	}
+
+	return Runbook{
+		ServerPrompt: server.ServerPrompt{Prompt: prompt, Handler: handler},
+		Tools: []string{
+			GET_NETWORK_FABRIC_TOOL_NAME,
+			CREATE_L3_ISOLATION_DOMAIN_TOOL_NAME,
+			CREATE_INTERNAL_NETWORK_TOOL_NAME,
+			CREATE_EXTERNAL_NETWORK_TOOL_NAME,
+			ENABLE_L3_ISOLATION_DOMAIN_TOOL_NAME,
+			COMMIT_NETWORK_FABRIC_TOOL_NAME,
+			GET_L3_ISOLATION_DOMAIN_ADMINISTRATIVE_STATE_TOOL_NAME,
+			GET_L3_ISOLATION_DOMAIN_CONFIGURATION_STATE_TOOL_NAME,
+		},
+	}
+}
+
+func createRoutePolicyFromPrefixes() Runbook {
+	prompt := mcp.NewPrompt(
+		CREATE_ROUTE_POLICY_PROMPT_NAME,
+		mcp.WithPromptDescription("Create a route policy that matches a set of prefixes and, optionally, communities."),
+		mcp.WithArgument("routePolicyName", mcp.RequiredArgument(), mcp.ArgumentDescription("The name of the new route policy.")),
+		mcp.WithArgument("fabricName", mcp.RequiredArgument(), mcp.ArgumentDescription("The network fabric the route policy belongs to.")),
+		mcp.WithArgument("prefixes", mcp.RequiredArgument(), mcp.ArgumentDescription("Comma separated IPv4 or IPv6 prefixes to match, e.g. 10.1.0.0/16,10.2.0.0/16.")),
+		mcp.WithArgument("communities", mcp.ArgumentDescription("Comma separated BGP communities to match, e.g. 65001:100.")),
+		mcp.WithArgument("action", mcp.ArgumentDescription("Permit or Deny for the matching routes. Defaults to Permit.")),
+		mcp.WithArgument("resourceGroupName", mcp.ArgumentDescription(PROMPT_RESOURCE_GROUP_DESCRIPTION)),
+		mcp.WithArgument("subscriptionId", mcp.ArgumentDescription(PROMPT_SUBSCRIPTION_ID_DESCRIPTION)),
+	)
+
+	handler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+		routePolicy := promptArgument(args, "routePolicyName", "")
+		fabric := promptArgument(args, "fabricName", "")
+		prefixes := promptArgument(args, "prefixes", "")
+		if routePolicy == "" || fabric == "" || prefixes == "" {
+			return nil, fmt.Errorf("routePolicyName, fabricName and prefixes are required")
+		}
+		communities := promptArgument(args, "communities", "")
+		action := promptArgument(args, "action", "Permit")
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Create the route policy '%s' on the network fabric '%s' in %s. It should %s routes matching the prefixes %s", routePolicy, fabric, promptScope(args), strings.ToLower(action), prefixes)
+		if communities != "" {
+			fmt.Fprintf(&text, " or carrying the communities %s", communities)
+		}
+		text.WriteString(".\n\nFollow these steps in order and stop at the first failure:\n")
+		fmt.Fprintf(&text, "1. Call %s for '%s' and note its ARM ID and location.\n", GET_NETWORK_FABRIC_TOOL_NAME, fabric)
+		fmt.Fprintf(&text, "2. Call %s for '%s-prefixes' with one ipPrefixRules entry per prefix: action Permit, sequenceNumber 10, 20, 30 and so on, the networkPrefix and condition EqualTo. Use dryRun true first.\n", CREATE_IP_PREFIX_TOOL_NAME, routePolicy)
+		step := 3
+		if communities != "" {
+			fmt.Fprintf(&text, "%d. Call %s for '%s-communities' with a single ipCommunityRules entry: action Permit, sequenceNumber 10 and communityMembers %s. Use dryRun true first.\n", step, CREATE_IP_COMMUNITY_TOOL_NAME, routePolicy, communities)
+			step++
+		}
+		fmt.Fprintf(&text, "%d. Call %s for '%s' with networkFabricId set to the fabric ID and the address family of the prefixes. Add a statement with sequenceNumber 10 whose condition has the ipPrefixId of step 2 and whose action has actionType %s.", step, CREATE_ROUTE_POLICY_TOOL_NAME, routePolicy, action)
+		if communities != "" {
+			fmt.Fprintf(&text, " Add a statement with sequenceNumber 20 whose condition has the ipCommunityIds of step 3 and the same action.")
+		}
+		text.WriteString(" Use dryRun true first and check that every referenced ID is found.\n")
+		step++
+		fmt.Fprintf(&text, "%d. Report the ID of the route policy. It takes effect once it is referenced, e.g. with %s or %s, and the fabric is committed with %s.\n", step, PATCH_L3_ISOLATION_DOMAIN_TOOL_NAME, PATCH_INTERNAL_NETWORK_TOOL_NAME, COMMIT_NETWORK_FABRIC_TOOL_NAME)
+
+		return runbookResult(prompt, text.String()), nil
+	}
+
+	return Runbook{
+		ServerPrompt: server.ServerPrompt{Prompt: prompt, Handler: handler},
+		Tools: []string{
+			GET_NETWORK_FABRIC_TOOL_NAME,
+			CREATE_IP_PREFIX_TOOL_NAME,
+			CREATE_IP_COMMUNITY_TOOL_NAME,
+			CREATE_ROUTE_POLICY_TOOL_NAME,
+		},
+	}
+}
+
+func triageUnhealthyLab() Runbook {
+	prompt := mcp.NewPrompt(
+		TRIAGE_LAB_PROMPT_NAME,
+		mcp.WithPromptDescription("Find out why a lab is unhealthy without changing anything."),
+		mcp.WithArgument("resourceGroupName", mcp.ArgumentDescription(PROMPT_RESOURCE_GROUP_DESCRIPTION)),
+		mcp.WithArgument("subscriptionId", mcp.ArgumentDescription(PROMPT_SUBSCRIPTION_ID_DESCRIPTION)),
+	)
+
+	handler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Triage the lab in %s. Only read; do not change anything unless the user explicitly asks.\n\n", promptScope(args))
+		text.WriteString("Follow these steps in order:\n")
+		fmt.Fprintf(&text, "1. Call %s and list every fabric and device that is not healthy.\n", GET_LAB_STATUS_TOOL_NAME)
+		fmt.Fprintf(&text, "2. For each unhealthy fabric, call %s and %s to see its states and devices.\n", GET_NETWORK_FABRIC_TOOL_NAME, LIST_DEVICES_NETWORK_FABRIC_TOOL_NAME)
+		fmt.Fprintf(&text, "3. For each unhealthy device, call %s and look at its provisioning, administrative and configuration state.\n", GET_NETWORK_DEVICE_TOOL_NAME)
+		fmt.Fprintf(&text, "4. Call %s with the queries devices_not_succeeded, l3isolationdomains_not_enabled and fabrics_not_provisioned, limited to this resource group, to spot related problems.\n", QUERY_RESOURCE_GRAPH_TOOL_NAME)
+		text.WriteString("5. Summarize the findings as a table of resource, state and likely cause, and propose next steps. ")
+		fmt.Fprintf(&text, "A device reboot (%s) or a fabric commit (%s) may only be proposed, never run, without the user's approval.\n", REBOOT_NETWORK_DEVICE_TOOL_NAME, COMMIT_NETWORK_FABRIC_TOOL_NAME)
+
+		return runbookResult(prompt, text.String()), nil
+	}
+
+	return Runbook{
+		ServerPrompt: server.ServerPrompt{Prompt: prompt, Handler: handler},
+		Tools: []string{
+			GET_LAB_STATUS_TOOL_NAME,
+			GET_NETWORK_FABRIC_TOOL_NAME,
+			LIST_DEVICES_NETWORK_FABRIC_TOOL_NAME,
+			GET_NETWORK_DEVICE_TOOL_NAME,
+			QUERY_RESOURCE_GRAPH_TOOL_NAME,
+		},
+	}
+}
+
+func tearDownDemoResourceGroup() Runbook {
+	prompt := mcp.NewPrompt(
+		TEAR_DOWN_RESOURCE_GROUP_PROMPT_NAME,
+		mcp.WithPromptDescription("Safely tear down a demo resource group: take isolation domains out of service, delete the network resources in dependency order, then delete the resource group."),
+		mcp.WithArgument("resourceGroupName", mcp.RequiredArgument(), mcp.ArgumentDescription("The demo resource group to tear down.")),
+		mcp.WithArgument("subscriptionId", mcp.ArgumentDescription(PROMPT_SUBSCRIPTION_ID_DESCRIPTION)),
+	)
+
+	handler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+		resourceGroup := promptArgument(args, "resourceGroupName", "")
+		if resourceGroup == "" {
+			return nil, fmt.Errorf("resourceGroupName is required")
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Tear down the demo resource group '%s'", resourceGroup)
+		if subscriptionId := promptArgument(args, "subscriptionId", ""); subscriptionId != "" {
+			fmt.Fprintf(&text, " of subscription '%s'", subscriptionId)
+		}
+		text.WriteString(".\n\n")
+		text.WriteString("Follow these steps in order and stop at the first failure:\n")
+		fmt.Fprintf(&text, "1. Call %s for '%s'. If it contains a network fabric or network devices, stop and tell the user: this runbook never deletes fabrics.\n", LIST_RESOURCES_IN_RG_TOOL_NAME, resourceGroup)
+		text.WriteString("2. Show the user the full list of resources that will be deleted and wait for their go-ahead.\n")
+		fmt.Fprintf(&text, "3. For every L3 isolation domain, call %s, then for every L2 isolation domain, call %s.\n", DISABLE_L3_ISOLATION_DOMAIN_TOOL_NAME, DISABLE_L2_ISOLATION_DOMAIN_TOOL_NAME)
+		fmt.Fprintf(&text, "4. Call %s on each fabric the isolation domains belong to, so the devices drop the configuration.\n", COMMIT_NETWORK_FABRIC_TOOL_NAME)
+		fmt.Fprintf(&text, "5. Delete the isolation domains with %s and %s.\n", DELETE_L3_ISOLATION_DOMAIN_TOOL_NAME, DELETE_L2_ISOLATION_DOMAIN_TOOL_NAME)
+		fmt.Fprintf(&text, "6. Delete the route policies with %s, then the IP prefixes, IP communities and IP extended communities they used with %s, %s and %s.\n", DELETE_ROUTE_POLICY_TOOL_NAME, DELETE_IP_PREFIX_TOOL_NAME, DELETE_IP_COMMUNITY_TOOL_NAME, DELETE_IP_EXT_COMMUNITY_TOOL_NAME)
+		fmt.Fprintf(&text, "7. Call %s for '%s' with dryRun true, show the plan, then call it again without dryRun.\n", DELETE_RESOURCE_GROUP_TOOL_NAME, resourceGroup)
+		text.WriteString("\nEvery delete asks the user to confirm; never work around a refusal or a protected resource.")
+
+		return runbookResult(prompt, text.String()), nil
+	}
+
+	return Runbook{
+		ServerPrompt: server.ServerPrompt{Prompt: prompt, Handler: handler},
+		Tools: []string{
+			LIST_RESOURCES_IN_RG_TOOL_NAME,
+			DISABLE_L3_ISOLATION_DOMAIN_TOOL_NAME,
+			DISABLE_L2_ISOLATION_DOMAIN_TOOL_NAME,
+			COMMIT_NETWORK_FABRIC_TOOL_NAME,
+			DELETE_L3_ISOLATION_DOMAIN_TOOL_NAME,
+			DELETE_L2_ISOLATION_DOMAIN_TOOL_NAME,
+			DELETE_ROUTE_POLICY_TOOL_NAME,
+			DELETE_IP_PREFIX_TOOL_NAME,
+			DELETE_IP_COMMUNITY_TOOL_NAME,
+			DELETE_IP_EXT_COMMUNITY_TOOL_NAME,
+			DELETE_RESOURCE_GROUP_TOOL_NAME,
+		},
+	}
+}
+
+func runbookResult(prompt mcp.Prompt, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(
+		prompt.Description,
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		},
+	)
+}
+
+func promptArgument(args map[string]string, name, fallback string) string {
+	if value := strings.TrimSpace(args[name]); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// promptScope describes where a runbook works, leaving the defaults to the session context.
+func promptScope(args map[string]string) string {
+	scope := "the resource group of the session context"
+	if resourceGroup := promptArgument(args, "resourceGroupName", ""); resourceGroup != "" {
+		scope = fmt.Sprintf("resource group '%s'", resourceGroup)
+	}
+	if subscriptionId := promptArgument(args, "subscriptionId", ""); subscriptionId != "" {
+		scope += fmt.Sprintf(" of subscription '%s'", subscriptionId)
+	}
+	return scope
+}
@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ProtectionPolicy marks resources that the mutating tools must never change, whatever tools are
+// enabled. It is loaded from the JSON file passed with -protection-policy.
+type ProtectionPolicy struct {
+	Rules []ProtectionRule `json:"rules"`
+}
+
+// ProtectionRule protects every resource matching at least one of its entries. Entries are
+// case-insensitive glob patterns such as "prod-*".
+type ProtectionRule struct {
+	Name           string   `json:"name"`
+	Subscriptions  []string `json:"subscriptions"`
+	ResourceGroups []string `json:"resourceGroups"`
+	// Fabrics protects the network fabrics themselves and every resource attached to them.
+	Fabrics []string `json:"fabrics"`
+	// Names matches the name of the resource, or of its parent isolation domain.
+	Names []string `json:"names"`
+}
+
+type protectionMatch struct {
+	rule    string
+	field   string
+	pattern string
+	value   string
+}
+
+func LoadProtectionPolicy(policyPath string) (*ProtectionPolicy, error) {
+	data, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protection policy %s: %v", policyPath, err)
+	}
+
+	var policy ProtectionPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal protection policy %s: %v", policyPath, err)
+	}
+
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("protection policy rule %d has no name", i+1)
+		}
+		for _, patterns := range [][]string{rule.Subscriptions, rule.ResourceGroups, rule.Fabrics, rule.Names} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("invalid pattern '%s' in protection policy rule '%s': %v", pattern, rule.Name, err)
+				}
+			}
+		}
+	}
+
+	return &policy, nil
+}
+
+// checkProtection refuses the operation when the target matches a rule of the protection policy.
+// It returns a nil result when the operation may proceed.
+func checkProtection(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, operation string, target TargetResource) (*mcp.CallToolResult, error) {
+	policy := clientRetriever.Protection
+	if policy == nil || len(policy.Rules) == 0 {
+		return nil, nil
+	}
+
+	if match, ok := policy.matchResource(target); ok {
+		return protectionRefusal(operation, target, match), nil
+	}
+
+	if !policy.protectsFabrics() {
+		return nil, nil
+	}
+
+	fabrics, err := targetFabrics(ctx, clientRetriever, cred, target)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Refusing to %s %s '%s': the protection policy protects network fabrics, but the fabric of the resource could not be determined: %v", operation, target.ResourceType, target.Name, err)), nil
+	}
+
+	for _, fabric := range fabrics {
+		if match, ok := policy.match("fabrics", fabric); ok {
+			return protectionRefusal(operation, target, match), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func protectionRefusal(operation string, target TargetResource, match protectionMatch) *mcp.CallToolResult {
+	resource := fmt.Sprintf("%s '%s'", target.ResourceType, target.Name)
+	if target.ResourceGroup != "" {
+		resource += fmt.Sprintf(" in resource group '%s'", target.ResourceGroup)
+	}
+
+	return mcp.NewToolResultError(fmt.Sprintf("Refusing to %s %s: it is protected by rule '%s' (%s pattern '%s' matches '%s'). Change the protection policy to allow this operation.",
+		operation, resource, match.rule, match.field, match.pattern, match.value))
+}
+
+func (policy *ProtectionPolicy) matchResource(target TargetResource) (protectionMatch, bool) {
+	if match, ok := policy.match("subscriptions", target.SubscriptionID); ok {
+		return match, true
+	}
+
+	resourceGroup := target.ResourceGroup
+	if target.ResourceType == RESOURCE_GROUP_RESOURCE_TYPE {
+		resourceGroup = target.Name
+	}
+	if match, ok := policy.match("resourceGroups", resourceGroup); ok {
+		return match, true
+	}
+
+	if target.ResourceType == NETWORK_FABRIC_RESOURCE_TYPE {
+		if match, ok := policy.match("fabrics", target.Name); ok {
+			return match, true
+		}
+	}
+	if target.FabricID != nil {
+		if match, ok := policy.match("fabrics", getNameFromID(*target.FabricID)); ok {
+			return match, true
+		}
+	}
+
+	for _, name := range []string{target.Name, target.ParentName} {
+		if match, ok := policy.match("names", name); ok {
+			return match, true
+		}
+	}
+
+	return protectionMatch{}, false
+}
+
+func (policy *ProtectionPolicy) match(field, value string) (protectionMatch, bool) {
+	if value == "" {
+		return protectionMatch{}, false
+	}
+
+	for _, rule := range policy.Rules {
+		var patterns []string
+		switch field {
+		case "subscriptions":
+			patterns = rule.Subscriptions
+		case "resourceGroups":
+			patterns = rule.ResourceGroups
+		case "fabrics":
+			patterns = rule.Fabrics
+		case "names":
+			patterns = rule.Names
+		}
+
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value)); matched {
+				return protectionMatch{rule: rule.Name, field: field, pattern: pattern, value: value}, true
+			}
+		}
+	}
+
+	return protectionMatch{}, false
+}
+
+func (policy *ProtectionPolicy) protectsFabrics() bool {
+	for _, rule := range policy.Rules {
+		if len(rule.Fabrics) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// targetFabrics looks up the names of the network fabrics the target belongs to.
+func targetFabrics(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, target TargetResource) ([]string, error) {
+	switch target.ResourceType {
+	case NETWORK_FABRIC_RESOURCE_TYPE:
+		return []string{target.Name}, nil
+	case RESOURCE_GROUP_RESOURCE_TYPE:
+		return listResourceNames(ctx, cred, clientRetriever.ClientOptions(), target.SubscriptionID, target.Name, NETWORK_FABRIC_RESOURCE_TYPE)
+	}
+
+	client, err := armresources.NewClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resources client: %v", err)
+	}
+
+	// internal and external networks belong to the fabric of their isolation domain
+	resourceID := target.ID()
+	if target.ParentName != "" {
+		resourceID = target.ParentID()
+	}
+
+	fabricID, err := resolveFabricID(ctx, client, resourceID, 0)
+	if err != nil || fabricID == "" {
+		return nil, err
+	}
+	return []string{getNameFromID(fabricID)}, nil
+}
+
+// resolveFabricID follows networkFabricId and networkRackId references until it finds the fabric.
+// A resource that does not exist yet, or is not attached to a fabric, resolves to "".
+func resolveFabricID(ctx context.Context, client *armresources.Client, resourceID string, depth int) (string, error) {
+	if depth > 2 {
+		return "", nil
+	}
+
+	res, exists, err := getGenericResource(ctx, client, resourceID)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	properties, ok := res.Properties.(map[string]any)
+	if !ok {
+		return "", nil
+	}
+	if fabricID, ok := properties["networkFabricId"].(string); ok && fabricID != "" {
+		return fabricID, nil
+	}
+	if rackID, ok := properties["networkRackId"].(string); ok && rackID != "" {
+		return resolveFabricID(ctx, client, rackID, depth+1)
+	}
+
+	return "", nil
+}
+
+// listResourceNames returns the names of the resources of a type in a resource group. A resource
+// group that does not exist has none.
+func listResourceNames(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, resourceType string) ([]string, error) {
+	client, err := armresources.NewClient(subscriptionId, cred, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resources client: %v", err)
+	}
+
+	filter := fmt.Sprintf("resourceType eq '%s'", resourceType)
+	pager := client.NewListByResourceGroupPager(resourceGroupName, &armresources.ClientListByResourceGroupOptions{
+		Filter: &filter,
+	})
+
+	names := []string{}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			var respErr *azcore.ResponseError
+			if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+				return names, nil
+			}
+			return nil, err
+		}
+		for _, resource := range page.Value {
+			if resource.Name != nil {
+				names = append(names, *resource.Name)
+			}
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// referenceTypes are the properties that reference other Nexus resources, by lower-cased name, with
+// the resource type they reference.
+var referenceTypes = map[string]string{
+	"networkfabricid":         NETWORK_FABRIC_RESOURCE_TYPE,
+	"importroutepolicyid":     ROUTE_POLICY_RESOURCE_TYPE,
+	"exportroutepolicyid":     ROUTE_POLICY_RESOURCE_TYPE,
+	"importipv4routepolicyid": ROUTE_POLICY_RESOURCE_TYPE,
+	"importipv6routepolicyid": ROUTE_POLICY_RESOURCE_TYPE,
+	"exportipv4routepolicyid": ROUTE_POLICY_RESOURCE_TYPE,
+	"exportipv6routepolicyid": ROUTE_POLICY_RESOURCE_TYPE,
+	"ingressaclid":            ACCESS_CONTROL_LIST_RESOURCE_TYPE,
+	"egressaclid":             ACCESS_CONTROL_LIST_RESOURCE_TYPE,
+	"ipprefixid":              IP_PREFIX_RESOURCE_TYPE,
+	"ipcommunityids":          IP_COMMUNITY_RESOURCE_TYPE,
+	"ipextendedcommunityids":  IP_EXT_COMMUNITY_RESOURCE_TYPE,
+}
+
+// propertyReference is a reference found in the properties of a call, with a way to replace it.
+type propertyReference struct {
+	path         string
+	resourceType string
+	value        string
+	set          func(id string)
+}
+
+// resolveReferences lets the reference properties of a create or patch call, such as networkFabricId
+// and ipCommunityIds, name the resource instead of giving its ARM ID. A name is looked up in the
+// resource group of the target and rg/name in that resource group, both in the subscription of the
+// target. Every reference must exist and, when it belongs to a fabric, to the fabric of the target:
+// the networkFabricId of the call, or else the fabric of the target or its isolation domain. The
+// properties are rewritten with the full IDs, and target.FabricID follows a resolved
+// networkFabricId. A result is returned when the call must stop.
+func resolveReferences(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, target *TargetResource, properties any) (*mcp.CallToolResult, error) {
+	propertiesJson, err := json.Marshal(properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal properties: %v", err)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(propertiesJson, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal properties: %v", err)
+	}
+
+	references := collectPropertyReferences("properties", values)
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	clients := map[string]*armresources.Client{}
+	client := func(subscriptionId string) (*armresources.Client, error) {
+		if client, ok := clients[subscriptionId]; ok {
+			return client, nil
+		}
+		client, err := armresources.NewClient(subscriptionId, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resources client: %v", err)
+		}
+		clients[subscriptionId] = client
+		return client, nil
+	}
+
+	violations := []string{}
+	// the fabric of every resolved reference that belongs to one, by reference path
+	referenceFabrics := map[string]string{}
+	fabricID := ""
+	for _, reference := range references {
+		id, problem := referenceID(*target, reference)
+		if problem != "" {
+			violations = append(violations, fmt.Sprintf("%s: %s", reference.path, problem))
+			continue
+		}
+
+		resourceID, _ := arm.ParseResourceID(id)
+		resourcesClient, err := client(resourceID.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		resource, exists, err := getGenericResource(ctx, resourcesClient, id)
+		if err != nil {
+			return armErrorResult(fmt.Sprintf("failed to resolve %s", reference.path), err)
+		}
+		if !exists {
+			violations = append(violations, fmt.Sprintf("%s: %s '%s' does not exist in resource group '%s' of subscription '%s'", reference.path, resourceID.ResourceType.String(), resourceID.Name, resourceID.ResourceGroupName, resourceID.SubscriptionID))
+			continue
+		}
+
+		reference.set(id)
+		if reference.resourceType == NETWORK_FABRIC_RESOURCE_TYPE {
+			fabricID = id
+		} else if resourceProperties, ok := resource.Properties.(map[string]any); ok {
+			if referenceFabric, ok := resourceProperties["networkFabricId"].(string); ok && referenceFabric != "" {
+				referenceFabrics[reference.path] = referenceFabric
+			}
+		}
+	}
+
+	if fabricID == "" && len(referenceFabrics) > 0 {
+		// internal and external networks belong to the fabric of their isolation domain
+		resourceID := target.ID()
+		if target.ParentName != "" {
+			resourceID = target.ParentID()
+		}
+		resourcesClient, err := client(target.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		if fabricID, err = resolveFabricID(ctx, resourcesClient, resourceID, 0); err != nil {
+			return armErrorResult("failed to look up the network fabric of the resource", err)
+		}
+	}
+	if fabricID != "" {
+		for _, reference := range references {
+			if referenceFabric, ok := referenceFabrics[reference.path]; ok && !strings.EqualFold(referenceFabric, fabricID) {
+				violations = append(violations, fmt.Sprintf("%s: '%s' belongs to network fabric '%s', not to network fabric '%s' of the %s", reference.path, getNameFromID(reference.value), getNameFromID(referenceFabric), getNameFromID(fabricID), target.ResourceType))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return validationErrorResult("references", violations), nil
+	}
+
+	propertiesJson, err = json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal properties: %v", err)
+	}
+	reflect.ValueOf(properties).Elem().SetZero()
+	if err := json.Unmarshal(propertiesJson, properties); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal properties: %v", err)
+	}
+	if fabricID != "" {
+		target.FabricID = &fabricID
+	}
+
+	return nil, nil
+}
+
+// collectPropertyReferences finds the reference properties in values, descending into objects and
+// arrays such as the statements of a route policy.
+func collectPropertyReferences(path string, value any) []propertyReference {
+	var references []propertyReference
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			child := v[key]
+			childPath := path + "." + key
+			resourceType, ok := referenceTypes[strings.ToLower(key)]
+			if !ok {
+				references = append(references, collectPropertyReferences(childPath, child)...)
+				continue
+			}
+
+			switch reference := child.(type) {
+			case string:
+				references = append(references, propertyReference{path: childPath, resourceType: resourceType, value: reference, set: func(id string) { v[key] = id }})
+			case []any:
+				for i, element := range reference {
+					if id, ok := element.(string); ok {
+						references = append(references, propertyReference{path: fmt.Sprintf("%s[%d]", childPath, i), resourceType: resourceType, value: id, set: func(id string) { reference[i] = id }})
+					}
+				}
+			}
+		}
+	case []any:
+		for i, child := range v {
+			references = append(references, collectPropertyReferences(fmt.Sprintf("%s[%d]", path, i), child)...)
+		}
+	}
+
+	return references
+}
+
+// referenceID returns the ARM ID a reference names, or a problem describing why it names none.
+func referenceID(target TargetResource, reference propertyReference) (string, string) {
+	value := strings.TrimSpace(reference.value)
+	if value == "" {
+		return "", "the reference is empty"
+	}
+
+	if strings.HasPrefix(value, "/") {
+		resourceID, err := arm.ParseResourceID(value)
+		if err != nil {
+			return "", fmt.Sprintf("'%s' is not a valid ARM ID", value)
+		}
+		if !strings.EqualFold(resourceID.ResourceType.String(), reference.resourceType) {
+			return "", fmt.Sprintf("'%s' is a %s, expected a %s", value, resourceID.ResourceType.String(), reference.resourceType)
+		}
+		return value, ""
+	}
+
+	resourceGroupName, name := target.ResourceGroup, value
+	switch parts := strings.Split(value, "/"); len(parts) {
+	case 1:
+	case 2:
+		resourceGroupName, name = parts[0], parts[1]
+	default:
+		return "", fmt.Sprintf("'%s' is not a name, resource group/name or ARM ID", value)
+	}
+	if resourceGroupName == "" || name == "" {
+		return "", fmt.Sprintf("'%s' is not a name, resource group/name or ARM ID", value)
+	}
+
+	return TargetResource{
+		SubscriptionID: target.SubscriptionID,
+		ResourceGroup:  resourceGroupName,
+		ResourceType:   reference.resourceType,
+		Name:           name,
+	}.ID(), ""
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ResourceGraphQuery is a canned inventory query that can be run by name.
+type ResourceGraphQuery struct {
+	Name        string
+	Description string
+	Query       string
+}
+
+// The source every query starts from, so only Managed Network Fabric resources can be returned.
+const resourceGraphSource = "Resources | where type startswith '" + RESOURCE_GRAPH_TYPE_PREFIX + "'"
+
+var resourceGraphQueries = []ResourceGraphQuery{
+	{
+		Name:        "l3isolationdomains_not_enabled",
+		Description: "L3 isolation domains whose administrative state is not Enabled",
+		Query: resourceGraphSource + `
+| where type =~ 'microsoft.managednetworkfabric/l3isolationdomains'
+| where tostring(properties.administrativeState) != 'Enabled'
+| project name, resourceGroup, subscriptionId, administrativeState = tostring(properties.administrativeState), configurationState = tostring(properties.configurationState), networkFabricId = tostring(properties.networkFabricId)`,
+	},
+	{
+		Name:        "l2isolationdomains_not_enabled",
+		Description: "L2 isolation domains whose administrative state is not Enabled",
+		Query: resourceGraphSource + `
+| where type =~ 'microsoft.managednetworkfabric/l2isolationdomains'
+| where tostring(properties.administrativeState) != 'Enabled'
+| project name, resourceGroup, subscriptionId, administrativeState = tostring(properties.administrativeState), configurationState = tostring(properties.configurationState), vlanId = toint(properties.vlanId)`,
+	},
+	{
+		Name:        "devices_not_succeeded",
+		Description: "Network devices whose provisioning state is not Succeeded",
+		Query: resourceGraphSource + `
+| where type =~ 'microsoft.managednetworkfabric/networkdevices'
+| where tostring(properties.provisioningState) != 'Succeeded'
+| project name, resourceGroup, subscriptionId, provisioningState = tostring(properties.provisioningState), administrativeState = tostring(properties.administrativeState), configurationState = tostring(properties.configurationState), networkRackId = tostring(properties.networkRackId)`,
+	},
+	{
+		Name:        "fabrics_not_provisioned",
+		Description: "Network fabrics whose configuration state is not Provisioned",
+		Query: resourceGraphSource + `
+| where type =~ 'microsoft.managednetworkfabric/networkfabrics'
+| where tostring(properties.configurationState) != 'Provisioned'
+| project name, resourceGroup, subscriptionId, provisioningState = tostring(properties.provisioningState), administrativeState = tostring(properties.administrativeState), configurationState = tostring(properties.configurationState)`,
+	},
+	{
+		Name:        "routepolicies_unused",
+		Description: "Route policies that no L3 isolation domain or network references",
+		Query: resourceGraphSource + `
+| where type =~ 'microsoft.managednetworkfabric/routepolicies'
+| extend policyId = tolower(id)
+| join kind=leftouter (
+    ` + resourceGraphSource + `
+    | where type in~ ('microsoft.managednetworkfabric/l3isolationdomains', 'microsoft.managednetworkfabric/l3isolationdomains/internalnetworks', 'microsoft.managednetworkfabric/l3isolationdomains/externalnetworks')
+    | extend policyId = extract_all(@'(/subscriptions/[^"]+/providers/microsoft\.managednetworkfabric/routepolicies/[^"]+)', tolower(tostring(properties)))
+    | mv-expand policyId to typeof(string)
+    | summarize by policyId
+) on policyId
+| where isempty(policyId1)
+| project name, resourceGroup, subscriptionId, id, provisioningState = tostring(properties.provisioningState), networkFabricId = tostring(properties.networkFabricId)`,
+	},
+	{
+		Name:        "inventory_by_type",
+		Description: "Number of Managed Network Fabric resources per type and subscription",
+		Query: resourceGraphSource + `
+| summarize count() by type, subscriptionId
+| order by type asc`,
+	},
+}
+
+// Operators that could bring in rows from outside the scoped source.
+var resourceGraphForbidden = regexp.MustCompile(`(?i)\b(join|union|lookup)\b`)
+
+func QueryResourceGraph(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return queryResourceGraph(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		queryName, _ := args["queryName"].(string)
+		fragment, _ := args["query"].(string)
+		if queryName == "" && strings.TrimSpace(fragment) == "" {
+			return nil, errors.New("query or query name missing")
+		}
+
+		query := resourceGraphSource
+		if queryName != "" {
+			canned, ok := findResourceGraphQuery(queryName)
+			if !ok {
+				return nil, fmt.Errorf("unknown query name '%s'", queryName)
+			}
+			query = canned.Query
+		}
+
+		fragment = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(fragment), "|"))
+		if fragment != "" {
+			if resourceGraphForbidden.MatchString(fragment) {
+				return nil, errors.New("query cannot use join, union or lookup; it only runs against Managed Network Fabric resources")
+			}
+			query += "\n| " + fragment
+		}
+
+		var subscriptions []*string
+		if ids, ok := args["subscriptionIds"].([]any); ok {
+			for _, id := range ids {
+				if id, ok := id.(string); ok && id != "" {
+					subscriptions = append(subscriptions, to.Ptr(id))
+				}
+			}
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		client, err := armresourcegraph.NewClient(cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource graph client: %v", err)
+		}
+
+		result := ResourceGraphResult{
+			Query: query,
+			Rows:  make([]map[string]any, 0),
+		}
+
+		var skipToken *string
+		for {
+			res, err := client.Resources(ctx, armresourcegraph.QueryRequest{
+				Query:         &query,
+				Subscriptions: subscriptions,
+				Options: &armresourcegraph.QueryRequestOptions{
+					ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+					Top:          to.Ptr(int32(RESOURCE_GRAPH_PAGE_SIZE)),
+					SkipToken:    skipToken,
+				},
+			}, nil)
+			if err != nil {
+				return armErrorResult("failed to query resource graph", err)
+			}
+
+			rows, _ := res.Data.([]any)
+			for _, row := range rows {
+				if row, ok := row.(map[string]any); ok {
+					result.Rows = append(result.Rows, row)
+				}
+			}
+			if res.TotalRecords != nil {
+				result.TotalRecords = *res.TotalRecords
+			}
+
+			skipToken = res.SkipToken
+			if skipToken == nil || *skipToken == "" {
+				break
+			}
+			if len(result.Rows) >= MAX_RESOURCE_GRAPH_ROWS {
+				result.Truncated = true
+				break
+			}
+		}
+		result.Count = len(result.Rows)
+
+		rowsJson, err := json.Marshal(result.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %v", err)
+		}
+
+		resultString := fmt.Sprintf("%d row(s)", result.Count)
+		if result.Truncated {
+			resultString += fmt.Sprintf(" of %d, truncated", result.TotalRecords)
+		}
+		resultString += ":\n" + string(rowsJson)
+
+		return mcp.NewToolResultStructured(result, resultString), nil
+	}
+}
+
+func queryResourceGraph() mcp.Tool {
+	names := make([]string, 0, len(resourceGraphQueries))
+	description := "Run an Azure Resource Graph (KQL) query over the Managed Network Fabric resources of all accessible subscriptions. Either run a canned query by name, pass a query fragment, or both to refine a canned query. Canned queries:\n"
+	for _, canned := range resourceGraphQueries {
+		names = append(names, canned.Name)
+		description += fmt.Sprintf("- %s: %s\n", canned.Name, canned.Description)
+	}
+
+	return mcp.NewTool(
+		QUERY_RESOURCE_GRAPH_TOOL_NAME,
+		mcp.WithString("queryName",
+			mcp.Description(RESOURCE_GRAPH_QUERY_NAME_DESCRIPTION),
+			mcp.Enum(names...),
+		),
+		mcp.WithString("query",
+			mcp.Description(RESOURCE_GRAPH_QUERY_DESCRIPTION),
+		),
+		mcp.WithArray("subscriptionIds",
+			mcp.Description(RESOURCE_GRAPH_SUBSCRIPTION_IDS_DESCRIPTION),
+			mcp.WithStringItems(),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceGraphResult](),
+		mcp.WithDescription(description),
+	)
+}
+
+func findResourceGraphQuery(name string) (ResourceGraphQuery, bool) {
+	for _, canned := range resourceGraphQueries {
+		if strings.EqualFold(canned.Name, name) {
+			return canned, true
+		}
+	}
+	return ResourceGraphQuery{}, false
+}
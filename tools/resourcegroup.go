@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -38,20 +39,33 @@ func CreateResourceGroup(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceType:   RESOURCE_GROUP_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, map[string]any{"location": location})
+		}
+
+		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create resource groups client: %v", err)
 		}
 
-		_, err = client.CreateOrUpdate(ctx, name, armresources.ResourceGroup{
+		res, err := client.CreateOrUpdate(ctx, name, armresources.ResourceGroup{
 			Location: &location,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to create resource group: %v", err)
+			return armErrorResult("failed to create resource group", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Resource Group '%s' created successfully in location '%s'", name, location)), nil
+		return newOperationResourceResult(OPERATION_CREATE, RESOURCE_GROUP_RESOURCE_TYPE, name, "", fmt.Sprintf("Resource Group '%s' created successfully in location '%s'", name, location), res)
 	}
 }
 
@@ -63,13 +77,15 @@ func createResourceGroup() mcp.Tool {
 			mcp.Description(RESOURCE_GROUP_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(RESOURCE_GROUP_LOCATION_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new Resource Group"),
 	)
 }
@@ -96,22 +112,42 @@ func DeleteResourceGroup(clientRetriever ServiceClientRetriever) (mcp.Tool, serv
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceType:   RESOURCE_GROUP_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create resource groups client: %v", err)
 		}
 
+		impact := resourceGroupDeleteImpact(ctx, clientRetriever, cred, subscriptionId, name)
+		if result, err := confirmAction(ctx, clientRetriever, fmt.Sprintf("Delete Resource Group '%s'", name), impact); result != nil || err != nil {
+			return result, err
+		}
+
+		timer := startOperationTimer(ctx, OPERATION_DELETE, RESOURCE_GROUP_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting resource group: %v", err)
+			return armErrorResult("failed to begin deleting resource group", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete resource group: %v", err)
+			return armErrorResult("failed to delete resource group", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Resource Group '%s' deleted successfully", name)), nil
+		return newOperationResult(OPERATION_DELETE, RESOURCE_GROUP_RESOURCE_TYPE, name, "", fmt.Sprintf("Resource Group '%s' deleted successfully", name)), nil
 	}
 }
 
@@ -123,9 +159,12 @@ func deleteResourceGroup() mcp.Tool {
 			mcp.Description(RESOURCE_GROUP_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete a Resource Group"),
 	)
 }
@@ -152,22 +191,17 @@ func GetResourceGroup(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, nil)
+		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create resource groups client: %v", err)
 		}
 
 		res, err := client.Get(ctx, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get resource group: %v", err)
+			return armErrorResult("failed to get resource group", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -179,9 +213,10 @@ func getResourceGroup() mcp.Tool {
 			mcp.Description(RESOURCE_GROUP_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get a Resource Group"),
 	)
 }
@@ -208,7 +243,7 @@ func ListResourcesInRG(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armresources.NewClient(subscriptionId, cred, nil)
+		client, err := armresources.NewClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create resources client: %v", err)
 		}
@@ -219,7 +254,7 @@ func ListResourcesInRG(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 		for pager.More() {
 			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get next page: %v", err)
+				return armErrorResult("failed to get next page", err)
 			}
 			resources = append(resources, page.Value...)
 		}
@@ -229,7 +264,19 @@ func ListResourcesInRG(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("failed to marshal response: %v", err)
 		}
 
-		return mcp.NewToolResultText(string(resJson)), nil
+		resourceList := ResourceList{
+			ResourceGroup: name,
+			Resources:     make([]ResourceSummary, 0, len(resources)),
+		}
+		for _, resource := range resources {
+			summary, err := newResourceSummary(resource)
+			if err != nil {
+				return nil, err
+			}
+			resourceList.Resources = append(resourceList.Resources, summary)
+		}
+
+		return mcp.NewToolResultStructured(resourceList, string(resJson)), nil
 	}
 }
 
@@ -241,9 +288,132 @@ func listResourcesInRG() mcp.Tool {
 			mcp.Description(RESOURCE_GROUP_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceList](),
 		mcp.WithDescription("List all resources in a Resource Group"),
 	)
 }
+
+func ListResourceGroups(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return listResourceGroups(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		subscriptionId, ok := args["subscriptionId"].(string)
+		if !ok || subscriptionId == "" {
+			return nil, errors.New("subscription id missing")
+		}
+
+		locationFilter, _ := args["locationFilter"].(string)
+
+		tagFilter := map[string]string{}
+		if tags, ok := args["tagFilter"].(map[string]any); ok {
+			for tagName, value := range tags {
+				tagValue, ok := value.(string)
+				if !ok && value != nil {
+					return nil, fmt.Errorf("tag filter value of '%s' must be a string", tagName)
+				}
+				tagFilter[tagName] = tagValue
+			}
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		client, err := armresources.NewResourceGroupsClient(subscriptionId, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource groups client: %v", err)
+		}
+
+		pager := client.NewListPager(nil)
+
+		resourceGroups := make([]*armresources.ResourceGroup, 0)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return armErrorResult("failed to get next page", err)
+			}
+			for _, resourceGroup := range page.Value {
+				if matchesLocation(resourceGroup.Location, locationFilter) && matchesTags(resourceGroup.Tags, tagFilter) {
+					resourceGroups = append(resourceGroups, resourceGroup)
+				}
+			}
+		}
+
+		resJson, err := json.Marshal(resourceGroups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %v", err)
+		}
+
+		resourceGroupList := ResourceGroupList{
+			SubscriptionID: subscriptionId,
+			ResourceGroups: make([]ResourceSummary, 0, len(resourceGroups)),
+		}
+		for _, resourceGroup := range resourceGroups {
+			summary, err := newResourceSummary(resourceGroup)
+			if err != nil {
+				return nil, err
+			}
+			resourceGroupList.ResourceGroups = append(resourceGroupList.ResourceGroups, summary)
+		}
+
+		return mcp.NewToolResultStructured(resourceGroupList, string(resJson)), nil
+	}
+}
+
+func listResourceGroups() mcp.Tool {
+	return mcp.NewTool(
+		LIST_RESOURCE_GROUPS_TOOL_NAME,
+		mcp.WithString("subscriptionId",
+			mcp.Description(RESOURCE_GROUP_SUBSCRIPTION_ID_DESCRIPTION),
+		),
+		mcp.WithString("locationFilter",
+			mcp.Description(RESOURCE_GROUP_LOCATION_FILTER_DESCRIPTION),
+		),
+		mcp.WithObject("tagFilter",
+			mcp.Description(RESOURCE_GROUP_TAG_FILTER_DESCRIPTION),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceGroupList](),
+		mcp.WithDescription("List the Resource Groups of a subscription, optionally filtered by location and tags"),
+	)
+}
+
+// matchesLocation compares Azure regions ignoring case and spaces, so "East US" matches "eastus".
+func matchesLocation(location *string, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	normalize := func(region string) string {
+		return strings.ToLower(strings.ReplaceAll(region, " ", ""))
+	}
+	return location != nil && normalize(*location) == normalize(filter)
+}
+
+// matchesTags reports whether tags carry every tag of the filter. Tag names are case-insensitive in
+// ARM; an empty filter value matches any value.
+func matchesTags(tags map[string]*string, filter map[string]string) bool {
+	for filterName, filterValue := range filter {
+		found := false
+		for tagName, tagValue := range tags {
+			if !strings.EqualFold(tagName, filterName) {
+				continue
+			}
+			if filterValue == "" || (tagValue != nil && *tagValue == filterValue) {
+				found = true
+			}
+			break
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
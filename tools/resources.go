@@ -0,0 +1,355 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// nexusResourceType maps a Network Fabric resource type onto an MCP resource template below
+// nexus://{subscription}/{rg}/.
+type nexusResourceType struct {
+	// path is the template path after the resource group, e.g. fabrics/{name}
+	path        string
+	name        string
+	description string
+	get         func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, child string) (any, error)
+	// list returns the names the last variable of the path can take. name is only set for child
+	// resources.
+	list func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name string) ([]string, error)
+}
+
+var nexusResourceTypes = []nexusResourceType{
+	{
+		path:        "fabrics/{name}",
+		name:        "Network Fabric",
+		description: "A Network Fabric with its provisioning, administrative and configuration state.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewNetworkFabricsClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create network fabrics client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, NETWORK_FABRIC_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "devices/{name}",
+		name:        "Network Device",
+		description: "A Network Device of a fabric.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewNetworkDevicesClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create network devices client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, NETWORK_DEVICE_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "l3isolationdomains/{name}",
+		name:        "L3 Isolation Domain",
+		description: "An L3 Isolation Domain.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewL3IsolationDomainsClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create L3 isolation domains client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, L3_ISOLATION_DOMAIN_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "l3isolationdomains/{name}/internalnetworks/{child}",
+		name:        "Internal Network",
+		description: "An Internal Network of an L3 Isolation Domain.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, child string) (any, error) {
+			client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create internal networks client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, child, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name string) ([]string, error) {
+			client, err := armmanagednetworkfabric.NewInternalNetworksClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create internal networks client: %v", err)
+			}
+			pager := client.NewListByL3IsolationDomainPager(resourceGroupName, name, nil)
+
+			names := []string{}
+			for pager.More() {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, network := range page.Value {
+					if network.Name != nil {
+						names = append(names, *network.Name)
+					}
+				}
+			}
+			return names, nil
+		},
+	},
+	{
+		path:        "l3isolationdomains/{name}/externalnetworks/{child}",
+		name:        "External Network",
+		description: "An External Network of an L3 Isolation Domain.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, child string) (any, error) {
+			client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create external networks client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, child, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name string) ([]string, error) {
+			client, err := armmanagednetworkfabric.NewExternalNetworksClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create external networks client: %v", err)
+			}
+			pager := client.NewListByL3IsolationDomainPager(resourceGroupName, name, nil)
+
+			names := []string{}
+			for pager.More() {
+				page, err := pager.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, network := range page.Value {
+					if network.Name != nil {
+						names = append(names, *network.Name)
+					}
+				}
+			}
+			return names, nil
+		},
+	},
+	{
+		path:        "l2isolationdomains/{name}",
+		name:        "L2 Isolation Domain",
+		description: "An L2 Isolation Domain.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewL2IsolationDomainsClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create L2 isolation domains client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, L2_ISOLATION_DOMAIN_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "routepolicies/{name}",
+		name:        "Route Policy",
+		description: "A Route Policy with its statements.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create route policies client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, ROUTE_POLICY_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "ipprefixes/{name}",
+		name:        "IP Prefix",
+		description: "An IP Prefix with its rules.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewIPPrefixesClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create IP prefixes client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, IP_PREFIX_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "ipcommunities/{name}",
+		name:        "IP Community",
+		description: "An IP Community with its rules.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewIPCommunitiesClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create IP communities client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, IP_COMMUNITY_RESOURCE_TYPE)
+		},
+	},
+	{
+		path:        "ipextendedcommunities/{name}",
+		name:        "IP Extended Community",
+		description: "An IP Extended Community with its rules.",
+		get: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, name, _ string) (any, error) {
+			client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(subscriptionId, cred, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create IP extended communities client: %v", err)
+			}
+			return client.Get(ctx, resourceGroupName, name, nil)
+		},
+		list: func(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptionId, resourceGroupName, _ string) ([]string, error) {
+			return listResourceNames(ctx, cred, options, subscriptionId, resourceGroupName, IP_EXT_COMMUNITY_RESOURCE_TYPE)
+		},
+	},
+}
+
+// ResourceTemplates returns the MCP resource templates of the Network Fabric resources, so clients
+// can attach a fabric, device or isolation domain as context without a tool call.
+func ResourceTemplates(clientRetriever ServiceClientRetriever) []server.ServerResourceTemplate {
+	templates := make([]server.ServerResourceTemplate, 0, len(nexusResourceTypes))
+	for _, resourceType := range nexusResourceTypes {
+		templates = append(templates, server.ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate(
+				resourceType.uriTemplate(),
+				resourceType.name,
+				mcp.WithTemplateDescription(resourceType.description),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: nexusResourceHandler(clientRetriever, resourceType),
+		})
+	}
+	return templates
+}
+
+func nexusResourceHandler(clientRetriever ServiceClientRetriever, resourceType nexusResourceType) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		variables := map[string]string{}
+		for _, name := range []string{"subscription", "rg", "name", "child"} {
+			variables[name] = templateArgument(request, name)
+		}
+
+		res, err := resourceType.fetch(ctx, clientRetriever, variables)
+		if err != nil {
+			if armErr, ok := decodeARMError(err); ok {
+				return nil, fmt.Errorf("failed to get %s.\n%s", resourceType.name, armErr.String())
+			}
+			return nil, fmt.Errorf("failed to get %s: %v", resourceType.name, err)
+		}
+
+		resJson, err := json.Marshal(res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %v", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(resJson),
+			},
+		}, nil
+	}
+}
+
+func (resourceType nexusResourceType) uriTemplate() string {
+	return NEXUS_URI_SCHEME + "{subscription}/{rg}/" + resourceType.path
+}
+
+// fetch gets the resource named by the variables of its URI template. ARM failures are returned
+// as is, so callers can decode them.
+func (resourceType nexusResourceType) fetch(ctx context.Context, clientRetriever ServiceClientRetriever, variables map[string]string) (any, error) {
+	subscriptionId := variables["subscription"]
+	if subscriptionId == "" {
+		return nil, errors.New("subscription id missing")
+	}
+
+	resourceGroupName := variables["rg"]
+	if resourceGroupName == "" {
+		return nil, errors.New("resource group name missing")
+	}
+
+	name := variables["name"]
+	if name == "" {
+		return nil, fmt.Errorf("%s name missing", resourceType.name)
+	}
+
+	cred, err := clientRetriever.Get()
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials: %v", err)
+	}
+
+	return resourceType.get(ctx, cred, clientRetriever.ClientOptions(), subscriptionId, resourceGroupName, name, variables["child"])
+}
+
+// parent returns the type of the resource a child resource type belongs to.
+func (resourceType nexusResourceType) parent() (nexusResourceType, bool) {
+	parentPath, _, ok := strings.Cut(resourceType.path, "/{name}/")
+	if !ok {
+		return nexusResourceType{}, false
+	}
+	return findNexusResourceType(parentPath + "/{name}")
+}
+
+func findNexusResourceType(path string) (nexusResourceType, bool) {
+	for _, resourceType := range nexusResourceTypes {
+		if resourceType.path == path {
+			return resourceType, true
+		}
+	}
+	return nexusResourceType{}, false
+}
+
+// findNexusResourceTemplate returns the resource type of a URI template, as passed in completion
+// requests.
+func findNexusResourceTemplate(uriTemplate string) (nexusResourceType, bool) {
+	for _, resourceType := range nexusResourceTypes {
+		if resourceType.uriTemplate() == uriTemplate {
+			return resourceType, true
+		}
+	}
+	return nexusResourceType{}, false
+}
+
+// matchNexusURI returns the resource type and template variables of a nexus:// resource URI.
+func matchNexusURI(uri string) (nexusResourceType, map[string]string, bool) {
+	for _, resourceType := range nexusResourceTypes {
+		template := mcp.NewResourceTemplate(resourceType.uriTemplate(), resourceType.name).URITemplate
+		if !template.Regexp().MatchString(uri) {
+			continue
+		}
+
+		variables := map[string]string{}
+		for name, value := range template.Match(uri) {
+			variables[name] = value.String()
+		}
+		return resourceType, variables, true
+	}
+	return nexusResourceType{}, nil, false
+}
+
+// templateArgument returns a variable of the matched URI template. mcp-go passes them as string
+// slices.
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,252 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OperationResult is the structured result of tools that change a resource.
+type OperationResult struct {
+	Operation     string `json:"operation" jsonschema:"The operation that was performed e.g. create or reboot."`
+	ResourceType  string `json:"resourceType" jsonschema:"The ARM resource type the operation was performed on."`
+	Name          string `json:"name" jsonschema:"The name of the resource."`
+	ResourceGroup string `json:"resourceGroup,omitempty" jsonschema:"The resource group of the resource."`
+	Message       string `json:"message" jsonschema:"A human readable summary of the outcome."`
+	// Resource is the created or updated resource, so its ID can be chained into the next call.
+	Resource *ResourceSummary `json:"resource,omitempty" jsonschema:"The resource as returned by ARM after a create or update."`
+	DryRun   bool             `json:"dryRun,omitempty" jsonschema:"Whether this was a dry run that changed nothing."`
+	Plan     *PlannedChange   `json:"plan,omitempty" jsonschema:"The planned change of a dry run."`
+}
+
+// ResourceSummary is the structured result of tools that get a resource.
+type ResourceSummary struct {
+	ID                  string            `json:"id" jsonschema:"The ARM ID of the resource."`
+	Name                string            `json:"name" jsonschema:"The name of the resource."`
+	Type                string            `json:"type" jsonschema:"The ARM resource type."`
+	Location            string            `json:"location,omitempty" jsonschema:"The Azure region of the resource."`
+	Tags                map[string]string `json:"tags,omitempty" jsonschema:"The tags of the resource."`
+	ProvisioningState   string            `json:"provisioningState,omitempty"`
+	AdministrativeState string            `json:"administrativeState,omitempty"`
+	ConfigurationState  string            `json:"configurationState,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty" jsonschema:"The full properties of the resource as returned by ARM."`
+}
+
+// StateResult is the structured result of tools that get a single state of a resource.
+type StateResult struct {
+	Name  string `json:"name" jsonschema:"The name of the resource."`
+	State string `json:"state" jsonschema:"The requested state of the resource."`
+}
+
+// ResourceList is the structured result of tools that list resources.
+type ResourceList struct {
+	ResourceGroup string            `json:"resourceGroup"`
+	Resources     []ResourceSummary `json:"resources"`
+}
+
+// NetworkDeviceList is the structured result of list_devices_network_fabric.
+type NetworkDeviceList struct {
+	FabricName string   `json:"fabricName"`
+	Devices    []string `json:"devices" jsonschema:"The names of the network devices in the fabric."`
+}
+
+// ResourceGroupList is the structured result of list_resourcegroups.
+type ResourceGroupList struct {
+	SubscriptionID string            `json:"subscriptionId"`
+	ResourceGroups []ResourceSummary `json:"resourceGroups"`
+}
+
+// SubscriptionList is the structured result of list_subscriptions.
+type SubscriptionList struct {
+	Subscriptions []SubscriptionSummary `json:"subscriptions"`
+}
+
+type SubscriptionSummary struct {
+	SubscriptionID string `json:"subscriptionId"`
+	DisplayName    string `json:"displayName"`
+	State          string `json:"state" jsonschema:"The subscription state e.g. Enabled or Disabled."`
+	TenantID       string `json:"tenantId,omitempty"`
+}
+
+// NexusResourceList is the structured result of find_nexus_resources.
+type NexusResourceList struct {
+	Resources []NexusResource `json:"resources"`
+	// Errors lists the subscriptions that could not be searched, so a partial result is not
+	// mistaken for a complete one.
+	Errors []string `json:"errors,omitempty" jsonschema:"Subscriptions that could not be searched and why."`
+}
+
+type NexusResource struct {
+	ID             string `json:"id" jsonschema:"The ARM ID of the resource."`
+	Name           string `json:"name"`
+	Type           string `json:"type" jsonschema:"The ARM resource type."`
+	SubscriptionID string `json:"subscriptionId"`
+	ResourceGroup  string `json:"resourceGroup"`
+	Location       string `json:"location,omitempty"`
+}
+
+// ResourceGraphResult is the structured result of query_resource_graph.
+type ResourceGraphResult struct {
+	Query        string           `json:"query" jsonschema:"The KQL query that was run."`
+	Count        int              `json:"count" jsonschema:"The number of rows returned."`
+	TotalRecords int64            `json:"totalRecords" jsonschema:"The number of rows matching the query."`
+	Truncated    bool             `json:"truncated,omitempty" jsonschema:"Whether rows were left out because the result is too large."`
+	Rows         []map[string]any `json:"rows"`
+}
+
+// RoutePolicyAnalysis is the structured result of analyze_routepolicy.
+type RoutePolicyAnalysis struct {
+	RoutePolicyID     string `json:"routePolicyId"`
+	AddressFamilyType string `json:"addressFamilyType,omitempty"`
+	DefaultAction     string `json:"defaultAction" jsonschema:"What happens to routes that no statement permits or denies."`
+	// DefaultActionSource says where the default action comes from, the implicit deny at the end of
+	// every policy or a statement that matches every route.
+	DefaultActionSource string               `json:"defaultActionSource"`
+	Statements          []StatementAnalysis  `json:"statements" jsonschema:"The statements in the order the devices evaluate them."`
+	Findings            []RoutePolicyFinding `json:"findings"`
+}
+
+type StatementAnalysis struct {
+	SequenceNumber int64  `json:"sequenceNumber"`
+	Condition      string `json:"condition" jsonschema:"A readable summary of what the statement matches."`
+	Action         string `json:"action"`
+	Reachable      bool   `json:"reachable" jsonschema:"Whether any route can reach this statement."`
+	ShadowedBy     *int64 `json:"shadowedBy,omitempty" jsonschema:"The earlier statement that handles every route this one matches."`
+}
+
+type RoutePolicyFinding struct {
+	Kind           string `json:"kind" jsonschema:"shadowed, sequence, reference, addressFamily or condition."`
+	SequenceNumber *int64 `json:"sequenceNumber,omitempty"`
+	Message        string `json:"message"`
+}
+
+// RouteSimulation is the structured result of simulate_routepolicy.
+type RouteSimulation struct {
+	RoutePolicyID    string                `json:"routePolicyId"`
+	Route            Route                 `json:"route" jsonschema:"The route as it was given."`
+	Action           string                `json:"action" jsonschema:"Permit or Deny, what the policy does with the route."`
+	MatchedStatement *int64                `json:"matchedStatement,omitempty" jsonschema:"The statement that permits or denies the route, unset for the implicit deny at the end of the policy."`
+	Result           *Route                `json:"result,omitempty" jsonschema:"The route after the modifications of the matching statements, unset when it is denied."`
+	Modifications    []string              `json:"modifications"`
+	Trace            []StatementEvaluation `json:"trace" jsonschema:"The statements evaluated, in order."`
+	Warnings         []string              `json:"warnings"`
+}
+
+type Route struct {
+	Prefix              string   `json:"prefix"`
+	Communities         []string `json:"communities"`
+	ExtendedCommunities []string `json:"extendedCommunities"`
+	LocalPreference     *int64   `json:"localPreference,omitempty"`
+}
+
+type StatementEvaluation struct {
+	SequenceNumber int64  `json:"sequenceNumber"`
+	Matched        bool   `json:"matched"`
+	Action         string `json:"action,omitempty" jsonschema:"The action of the statement, set when it matched."`
+	Reason         string `json:"reason"`
+}
+
+// RoutePolicyBuild is the structured result of build_routepolicy.
+type RoutePolicyBuild struct {
+	RoutePolicyID string          `json:"routePolicyId"`
+	Message       string          `json:"message" jsonschema:"A human readable summary of the outcome."`
+	DryRun        bool            `json:"dryRun,omitempty" jsonschema:"Whether this was a dry run that changed nothing."`
+	Resources     []BuiltResource `json:"resources" jsonschema:"The resources in the order they are created, the route policy last."`
+	Warnings      []string        `json:"warnings,omitempty"`
+}
+
+type BuiltResource struct {
+	ID         string         `json:"id" jsonschema:"The ARM ID of the resource."`
+	Name       string         `json:"name"`
+	Type       string         `json:"type" jsonschema:"The ARM resource type."`
+	Status     string         `json:"status" jsonschema:"planned or created."`
+	Properties map[string]any `json:"properties" jsonschema:"The properties sent to ARM."`
+}
+
+func newOperationResult(operation, resourceType, name, resourceGroup, message string) *mcp.CallToolResult {
+	return mcp.NewToolResultStructured(OperationResult{
+		Operation:     operation,
+		ResourceType:  resourceType,
+		Name:          name,
+		ResourceGroup: resourceGroup,
+		Message:       message,
+	}, message)
+}
+
+// newOperationResourceResult is newOperationResult for create and update operations, carrying the
+// resulting resource and appending its ID and states to the message.
+func newOperationResourceResult(operation, resourceType, name, resourceGroup, message string, resource any) (*mcp.CallToolResult, error) {
+	summary, err := newResourceSummary(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	text := message + "\n"
+	text += fmt.Sprintf("- ID: %s\n", summary.ID)
+	if summary.ProvisioningState != "" {
+		text += fmt.Sprintf("- Provisioning State: %s\n", summary.ProvisioningState)
+	}
+	if summary.AdministrativeState != "" {
+		text += fmt.Sprintf("- Administrative State: %s\n", summary.AdministrativeState)
+	}
+	if summary.ConfigurationState != "" {
+		text += fmt.Sprintf("- Configuration State: %s\n", summary.ConfigurationState)
+	}
+
+	return mcp.NewToolResultStructured(OperationResult{
+		Operation:     operation,
+		ResourceType:  resourceType,
+		Name:          name,
+		ResourceGroup: resourceGroup,
+		Message:       message,
+		Resource:      &summary,
+	}, text), nil
+}
+
+// newResourceResult returns the SDK response as JSON text together with its structured summary.
+func newResourceResult(resource any) (*mcp.CallToolResult, error) {
+	resJson, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %v", err)
+	}
+
+	summary, err := summarizeResourceJSON(resJson)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(summary, string(resJson)), nil
+}
+
+func newResourceSummary(resource any) (ResourceSummary, error) {
+	resJson, err := json.Marshal(resource)
+	if err != nil {
+		return ResourceSummary{}, fmt.Errorf("failed to marshal resource: %v", err)
+	}
+
+	return summarizeResourceJSON(resJson)
+}
+
+func summarizeResourceJSON(resJson []byte) (ResourceSummary, error) {
+	var summary ResourceSummary
+	if err := json.Unmarshal(resJson, &summary); err != nil {
+		return summary, fmt.Errorf("failed to summarize resource: %v", err)
+	}
+	summary.fillStates()
+
+	return summary, nil
+}
+
+// fillStates lifts the well-known states out of the properties bag.
+func (summary *ResourceSummary) fillStates() {
+	state := func(key string) string {
+		if value, ok := summary.Properties[key].(string); ok {
+			return value
+		}
+		return ""
+	}
+	summary.ProvisioningState = state("provisioningState")
+	summary.AdministrativeState = state("administrativeState")
+	summary.ConfigurationState = state("configurationState")
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -28,14 +27,9 @@ func CreateRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, errors.New("location missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var properties armmanagednetworkfabric.RoutePolicyProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &properties); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &properties); err != nil {
+			return nil, err
 		}
 
 		resourceGroupName, ok := args["resourceGroupName"].(string)
@@ -53,26 +47,46 @@ func CreateRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   ROUTE_POLICY_RESOURCE_TYPE,
+			Name:           name,
+			FabricID:       properties.NetworkFabricID,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &properties); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_CREATE, target, properties)
+		}
+
+		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create route policies client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_CREATE, ROUTE_POLICY_RESOURCE_TYPE)
 		poller, err := client.BeginCreate(ctx, resourceGroupName, name, armmanagednetworkfabric.RoutePolicy{
 			Location:   &location,
 			Properties: &properties,
 		}, nil)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin creating route policy: %v", err)
+			return armErrorResult("failed to begin creating route policy", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create route policy: %v", err)
+			return armErrorResult("failed to create route policy", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Route Policy '%s' created successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_CREATE, ROUTE_POLICY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("Route Policy '%s' created successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -84,21 +98,19 @@ func createRoutePolicy() mcp.Tool {
 			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("location",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_LOCATION_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description(ROUTE_POLICY_PROPERTIES_DESCRIPTION),
-		),
+		propertiesParameter[armmanagednetworkfabric.RoutePolicyProperties](ROUTE_POLICY_PROPERTIES_DESCRIPTION),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Create a new Route Policy"),
 	)
 }
@@ -130,22 +142,38 @@ func DeleteRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   ROUTE_POLICY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_DELETE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_DELETE, target, nil)
+		}
+
+		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create route policies client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_DELETE, ROUTE_POLICY_RESOURCE_TYPE)
 		poller, err := client.BeginDelete(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin deleting route policy: %v", err)
+			return armErrorResult("failed to begin deleting route policy", err)
 		}
 
 		_, err = poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete route policy: %v", err)
+			return armErrorResult("failed to delete route policy", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Route Policy '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResult(OPERATION_DELETE, ROUTE_POLICY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("Route Policy '%s' deleted successfully from resource group '%s'", name, resourceGroupName)), nil
 	}
 }
 
@@ -157,13 +185,15 @@ func deleteRoutePolicy() mcp.Tool {
 			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Delete a Route Policy"),
 	)
 }
@@ -190,14 +220,9 @@ func PatchRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, errors.New("subscription id missing")
 		}
 
-		propertiesStr, ok := args["properties"].(string)
-		if !ok || propertiesStr == "" {
-			return nil, errors.New("properties missing")
-		}
-
 		var patchProps armmanagednetworkfabric.RoutePolicyPatchableProperties
-		if err := json.Unmarshal([]byte(propertiesStr), &patchProps); err != nil {
-			return nil, fmt.Errorf("error unmarshalling properties: %v", err)
+		if err := parseProperties(args, &patchProps); err != nil {
+			return nil, err
 		}
 		properties := armmanagednetworkfabric.RoutePolicyPatch{
 			Properties: &patchProps,
@@ -208,22 +233,41 @@ func PatchRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, nil)
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   ROUTE_POLICY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &target, &patchProps); result != nil || err != nil {
+			return result, err
+		}
+		if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_UPDATE, target); result != nil || err != nil {
+			return result, err
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			return planChange(ctx, clientRetriever, cred, OPERATION_UPDATE, target, patchProps)
+		}
+
+		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create route policies client: %v", err)
 		}
 
+		timer := startOperationTimer(ctx, OPERATION_UPDATE, ROUTE_POLICY_RESOURCE_TYPE)
 		poller, err := client.BeginUpdate(ctx, resourceGroupName, name, properties, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin updating route policy: %v", err)
+			return armErrorResult("failed to begin updating route policy", err)
 		}
 
-		_, err = poller.PollUntilDone(ctx, nil)
+		res, err := poller.PollUntilDone(ctx, nil)
+		timer.stop(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update route policy: %v", err)
+			return armErrorResult("failed to update route policy", err)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Route Policy '%s' updated successfully in resource group '%s'", name, resourceGroupName)), nil
+		return newOperationResourceResult(OPERATION_UPDATE, ROUTE_POLICY_RESOURCE_TYPE, name, resourceGroupName, fmt.Sprintf("Route Policy '%s' updated successfully in resource group '%s'", name, resourceGroupName), res)
 	}
 }
 
@@ -234,18 +278,17 @@ func patchRoutePolicy() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
 		),
-		mcp.WithString("properties",
-			mcp.Required(),
-			mcp.Description("The properties to update on the Route Policy. This should be a JSON string."),
-		),
+		propertiesParameter[armmanagednetworkfabric.RoutePolicyPatchableProperties]("The properties to update on the Route Policy. This should be a JSON object."),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[OperationResult](),
 		mcp.WithDescription("Patch a Route Policy"),
 	)
 }
@@ -277,22 +320,17 @@ func GetRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.To
 			return nil, fmt.Errorf("error getting credentials: %v", err)
 		}
 
-		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, nil)
+		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, clientRetriever.ClientOptions())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create route policies client: %v", err)
 		}
 
 		res, err := client.Get(ctx, resourceGroupName, name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get route policy: %v", err)
+			return armErrorResult("failed to get route policy", err)
 		}
 
-		resJson, err := json.Marshal(res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %v", err)
-		}
-
-		return mcp.NewToolResultText(string(resJson)), nil
+		return newResourceResult(res)
 	}
 }
 
@@ -304,13 +342,13 @@ func getRoutePolicy() mcp.Tool {
 			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
 		),
 		mcp.WithString("resourceGroupName",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
 		),
 		mcp.WithString("subscriptionId",
-			mcp.Required(),
 			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
 		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ResourceSummary](),
 		mcp.WithDescription("Get a Route Policy"),
 	)
 }
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+)
+
+// Fixtures shared by the tests of the route policy analyzer, simulator and builder.
+
+const (
+	testSubscriptionID  = "00000000-0000-0000-0000-000000000000"
+	testResourceGroupID = "/subscriptions/" + testSubscriptionID + "/resourceGroups/rg/providers/Microsoft.ManagedNetworkFabric"
+)
+
+func testIPPrefixID(name string) string {
+	return testResourceGroupID + "/ipPrefixes/" + name
+}
+
+func testIPCommunityID(name string) string {
+	return testResourceGroupID + "/ipCommunities/" + name
+}
+
+func testPrefixRule(action armmanagednetworkfabric.CommunityActionTypes, prefix string, condition armmanagednetworkfabric.Condition, length string) *armmanagednetworkfabric.IPPrefixRule {
+	rule := &armmanagednetworkfabric.IPPrefixRule{
+		Action:         to.Ptr(action),
+		SequenceNumber: to.Ptr(int64(10)),
+		NetworkPrefix:  to.Ptr(prefix),
+	}
+	if condition != "" {
+		rule.Condition = to.Ptr(condition)
+		rule.SubnetMaskLength = to.Ptr(length)
+	}
+	return rule
+}
+
+func testStatement(sequenceNumber int64, action armmanagednetworkfabric.RoutePolicyActionType, condition *armmanagednetworkfabric.StatementConditionProperties) *armmanagednetworkfabric.RoutePolicyStatementProperties {
+	return &armmanagednetworkfabric.RoutePolicyStatementProperties{
+		SequenceNumber: to.Ptr(sequenceNumber),
+		Condition:      condition,
+		Action:         &armmanagednetworkfabric.StatementActionProperties{ActionType: to.Ptr(action)},
+	}
+}
+
+func testPrefixCondition(name string) *armmanagednetworkfabric.StatementConditionProperties {
+	return &armmanagednetworkfabric.StatementConditionProperties{IPPrefixID: to.Ptr(testIPPrefixID(name))}
+}
+
+func testCommunityCondition(conditionType armmanagednetworkfabric.RoutePolicyConditionType, prefix string, communities ...string) *armmanagednetworkfabric.StatementConditionProperties {
+	condition := &armmanagednetworkfabric.StatementConditionProperties{Type: to.Ptr(conditionType)}
+	if prefix != "" {
+		condition.IPPrefixID = to.Ptr(testIPPrefixID(prefix))
+	}
+	for _, community := range communities {
+		condition.IPCommunityIDs = append(condition.IPCommunityIDs, to.Ptr(testIPCommunityID(community)))
+	}
+	return condition
+}
+
+// testRoutePolicyModel returns a model of the statements with the IP prefixes, keyed by name.
+func testRoutePolicyModel(prefixes map[string][]*armmanagednetworkfabric.IPPrefixRule, statements ...*armmanagednetworkfabric.RoutePolicyStatementProperties) *routePolicyModel {
+	model := &routePolicyModel{
+		policy: armmanagednetworkfabric.RoutePolicy{
+			ID: to.Ptr(testResourceGroupID + "/routePolicies/rp1"),
+			Properties: &armmanagednetworkfabric.RoutePolicyProperties{
+				AddressFamilyType: to.Ptr(armmanagednetworkfabric.AddressFamilyTypeIPv4),
+				Statements:        statements,
+			},
+		},
+		prefixes:          map[string]*armmanagednetworkfabric.IPPrefixProperties{},
+		communities:       map[string]*armmanagednetworkfabric.IPCommunityProperties{},
+		extCommunities:    map[string]*armmanagednetworkfabric.IPExtendedCommunityProperties{},
+		referenceProblems: map[string]string{},
+	}
+	for name, rules := range prefixes {
+		model.prefixes[strings.ToLower(testIPPrefixID(name))] = &armmanagednetworkfabric.IPPrefixProperties{IPPrefixRules: rules}
+	}
+	return model
+}
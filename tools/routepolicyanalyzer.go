@@ -0,0 +1,641 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/netip"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// routePolicyModel is a route policy together with the IP prefixes and communities its statements
+// reference, keyed by lower-cased ARM ID.
+type routePolicyModel struct {
+	policy         armmanagednetworkfabric.RoutePolicy
+	prefixes       map[string]*armmanagednetworkfabric.IPPrefixProperties
+	communities    map[string]*armmanagednetworkfabric.IPCommunityProperties
+	extCommunities map[string]*armmanagednetworkfabric.IPExtendedCommunityProperties
+	// referenceProblems describes the referenced objects that are missing, being deleted or could
+	// not be fetched.
+	referenceProblems map[string]string
+}
+
+// referenceKinds names the kinds of objects route policy statements reference.
+var referenceKinds = map[string]string{
+	IP_PREFIX_RESOURCE_TYPE:        "IP prefix",
+	IP_COMMUNITY_RESOURCE_TYPE:     "IP community",
+	IP_EXT_COMMUNITY_RESOURCE_TYPE: "IP extended community",
+}
+
+// DUPLICATE_SEQUENCE_NUMBER_WARNING is reported for a sequence number used by more than one
+// statement. The devices configure the statements as the same route map entry, each replacing the
+// one listed before it.
+const DUPLICATE_SEQUENCE_NUMBER_WARNING = "sequence number %d is used by more than one statement; the devices keep one route map entry per sequence number, so only the statement listed last takes effect"
+
+// matchCriterion is one thing a statement condition matches on: an IP prefix, a set of IP
+// communities or a set of IP extended communities.
+type matchCriterion struct {
+	resourceType string
+	ids          []string
+}
+
+// prefixMatch is the set of routes an IP prefix rule matches: prefixes within prefix whose length is
+// between low and high.
+type prefixMatch struct {
+	prefix    netip.Prefix
+	low, high int
+}
+
+func AnalyzeRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return analyzeRoutePolicy(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		name, ok := args["name"].(string)
+		if !ok || name == "" {
+			return nil, errors.New("Route Policy name missing")
+		}
+
+		resourceGroupName, ok := args["resourceGroupName"].(string)
+		if !ok || resourceGroupName == "" {
+			return nil, errors.New("resource group name missing")
+		}
+
+		subscriptionId, ok := args["subscriptionId"].(string)
+		if !ok || subscriptionId == "" {
+			return nil, errors.New("subscription id missing")
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		model, err := loadRoutePolicy(ctx, clientRetriever, cred, subscriptionId, resourceGroupName, name)
+		if err != nil {
+			return armErrorResult("failed to get route policy", err)
+		}
+
+		analysis := model.analyze()
+		return mcp.NewToolResultStructured(analysis, analysis.String()), nil
+	}
+}
+
+func analyzeRoutePolicy() mcp.Tool {
+	return mcp.NewTool(
+		ANALYZE_ROUTE_POLICY_TOOL_NAME,
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
+		),
+		mcp.WithString("resourceGroupName",
+			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
+		),
+		mcp.WithString("subscriptionId",
+			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[RoutePolicyAnalysis](),
+		mcp.WithDescription("Analyze a Route Policy together with the IP prefixes, IP communities and IP extended communities its statements reference. Reports statements shadowed by earlier ones, duplicate or out-of-order sequence numbers, missing or deleted references, address family mismatches and the effective default action."),
+	)
+}
+
+// loadRoutePolicy gets a route policy and every object its statements reference. Only a failure to
+// get the policy itself is returned; problems with references are recorded in the model.
+func loadRoutePolicy(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, resourceGroupName, name string) (*routePolicyModel, error) {
+	client, err := armmanagednetworkfabric.NewRoutePoliciesClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create route policies client: %v", err)
+	}
+
+	res, err := client.Get(ctx, resourceGroupName, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &routePolicyModel{
+		policy:            res.RoutePolicy,
+		prefixes:          make(map[string]*armmanagednetworkfabric.IPPrefixProperties),
+		communities:       make(map[string]*armmanagednetworkfabric.IPCommunityProperties),
+		extCommunities:    make(map[string]*armmanagednetworkfabric.IPExtendedCommunityProperties),
+		referenceProblems: make(map[string]string),
+	}
+
+	for id, resourceType := range model.references() {
+		model.fetchReference(ctx, clientRetriever, cred, id, resourceType)
+	}
+
+	return model, nil
+}
+
+// references returns the lower-cased IDs referenced by the conditions and actions of the
+// statements, with the resource type each is referenced as.
+func (model *routePolicyModel) references() map[string]string {
+	references := map[string]string{}
+	add := func(resourceType string, ids ...*string) {
+		for _, id := range ids {
+			if id != nil && *id != "" {
+				references[strings.ToLower(*id)] = resourceType
+			}
+		}
+	}
+
+	for _, statement := range model.statements() {
+		if condition := statement.Condition; condition != nil {
+			add(IP_PREFIX_RESOURCE_TYPE, condition.IPPrefixID)
+			add(IP_COMMUNITY_RESOURCE_TYPE, condition.IPCommunityIDs...)
+			add(IP_EXT_COMMUNITY_RESOURCE_TYPE, condition.IPExtendedCommunityIDs...)
+		}
+		if action := statement.Action; action != nil {
+			if communities := action.IPCommunityProperties; communities != nil {
+				for _, list := range []*armmanagednetworkfabric.IPCommunityIDList{communities.Add, communities.Delete, communities.Set} {
+					if list != nil {
+						add(IP_COMMUNITY_RESOURCE_TYPE, list.IPCommunityIDs...)
+					}
+				}
+			}
+			if extCommunities := action.IPExtendedCommunityProperties; extCommunities != nil {
+				for _, list := range []*armmanagednetworkfabric.IPExtendedCommunityIDList{extCommunities.Add, extCommunities.Delete, extCommunities.Set} {
+					if list != nil {
+						add(IP_EXT_COMMUNITY_RESOURCE_TYPE, list.IPExtendedCommunityIDs...)
+					}
+				}
+			}
+		}
+	}
+
+	return references
+}
+
+func (model *routePolicyModel) fetchReference(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, id, resourceType string) {
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		model.referenceProblems[id] = "is not a valid ARM ID"
+		return
+	}
+	if !strings.EqualFold(resourceID.ResourceType.String(), resourceType) {
+		model.referenceProblems[id] = fmt.Sprintf("is a %s, not a %s", resourceID.ResourceType.String(), resourceType)
+		return
+	}
+
+	var provisioningState *armmanagednetworkfabric.ProvisioningState
+	switch resourceType {
+	case IP_PREFIX_RESOURCE_TYPE:
+		var client *armmanagednetworkfabric.IPPrefixesClient
+		if client, err = armmanagednetworkfabric.NewIPPrefixesClient(resourceID.SubscriptionID, cred, clientRetriever.ClientOptions()); err == nil {
+			var res armmanagednetworkfabric.IPPrefixesClientGetResponse
+			if res, err = client.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, nil); err == nil && res.Properties != nil {
+				model.prefixes[id] = res.Properties
+				provisioningState = res.Properties.ProvisioningState
+			}
+		}
+	case IP_COMMUNITY_RESOURCE_TYPE:
+		var client *armmanagednetworkfabric.IPCommunitiesClient
+		if client, err = armmanagednetworkfabric.NewIPCommunitiesClient(resourceID.SubscriptionID, cred, clientRetriever.ClientOptions()); err == nil {
+			var res armmanagednetworkfabric.IPCommunitiesClientGetResponse
+			if res, err = client.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, nil); err == nil && res.Properties != nil {
+				model.communities[id] = res.Properties
+				provisioningState = res.Properties.ProvisioningState
+			}
+		}
+	case IP_EXT_COMMUNITY_RESOURCE_TYPE:
+		var client *armmanagednetworkfabric.IPExtendedCommunitiesClient
+		if client, err = armmanagednetworkfabric.NewIPExtendedCommunitiesClient(resourceID.SubscriptionID, cred, clientRetriever.ClientOptions()); err == nil {
+			var res armmanagednetworkfabric.IPExtendedCommunitiesClientGetResponse
+			if res, err = client.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, nil); err == nil && res.Properties != nil {
+				model.extCommunities[id] = res.Properties
+				provisioningState = res.Properties.ProvisioningState
+			}
+		}
+	}
+
+	switch {
+	case err != nil:
+		if armErr, ok := decodeARMError(err); ok && armErr.StatusCode == http.StatusNotFound {
+			model.referenceProblems[id] = "does not exist"
+		} else if ok {
+			model.referenceProblems[id] = fmt.Sprintf("could not be fetched: %s %s", armErr.Code, armErr.Message)
+		} else {
+			model.referenceProblems[id] = fmt.Sprintf("could not be fetched: %v", err)
+		}
+	case provisioningState != nil && *provisioningState == armmanagednetworkfabric.ProvisioningStateDeleting:
+		model.referenceProblems[id] = "is being deleted"
+	case provisioningState != nil && *provisioningState == armmanagednetworkfabric.ProvisioningStateFailed:
+		model.referenceProblems[id] = "failed to provision"
+	}
+}
+
+func (model *routePolicyModel) statements() []*armmanagednetworkfabric.RoutePolicyStatementProperties {
+	if model.policy.Properties == nil {
+		return nil
+	}
+	return model.policy.Properties.Statements
+}
+
+// orderedStatements returns the statements in the order the devices evaluate them, by ascending
+// sequence number. Statements without a sequence number cannot be placed and are left out.
+func (model *routePolicyModel) orderedStatements() []*armmanagednetworkfabric.RoutePolicyStatementProperties {
+	ordered := make([]*armmanagednetworkfabric.RoutePolicyStatementProperties, 0, len(model.statements()))
+	for _, statement := range model.statements() {
+		if statement != nil && statement.SequenceNumber != nil {
+			ordered = append(ordered, statement)
+		}
+	}
+	slices.SortStableFunc(ordered, func(a, b *armmanagednetworkfabric.RoutePolicyStatementProperties) int {
+		return cmp.Compare(*a.SequenceNumber, *b.SequenceNumber)
+	})
+	return ordered
+}
+
+func (model *routePolicyModel) analyze() RoutePolicyAnalysis {
+	analysis := RoutePolicyAnalysis{
+		RoutePolicyID:       stringValue(model.policy.ID),
+		DefaultAction:       string(armmanagednetworkfabric.RoutePolicyActionTypeDeny),
+		DefaultActionSource: "the implicit deny at the end of every route policy",
+		Statements:          []StatementAnalysis{},
+		Findings:            []RoutePolicyFinding{},
+	}
+	finding := func(kind string, sequenceNumber *int64, format string, a ...any) {
+		analysis.Findings = append(analysis.Findings, RoutePolicyFinding{Kind: kind, SequenceNumber: sequenceNumber, Message: fmt.Sprintf(format, a...)})
+	}
+
+	var addressFamily *armmanagednetworkfabric.AddressFamilyType
+	if model.policy.Properties != nil {
+		addressFamily = model.policy.Properties.AddressFamilyType
+	}
+	if addressFamily != nil {
+		analysis.AddressFamilyType = string(*addressFamily)
+	}
+
+	// sequence numbers, in the order the statements are listed
+	seen := map[int64]bool{}
+	var previous *int64
+	for i, statement := range model.statements() {
+		if statement == nil || statement.SequenceNumber == nil {
+			finding("sequence", nil, "statements[%d] has no sequence number and is ignored", i)
+			continue
+		}
+		sequenceNumber := statement.SequenceNumber
+		if seen[*sequenceNumber] {
+			finding("sequence", sequenceNumber, DUPLICATE_SEQUENCE_NUMBER_WARNING, *sequenceNumber)
+		}
+		seen[*sequenceNumber] = true
+		if previous != nil && *sequenceNumber < *previous {
+			finding("sequence", sequenceNumber, "statement %d is listed after statement %d, but the devices evaluate statements by ascending sequence number", *sequenceNumber, *previous)
+		}
+		previous = sequenceNumber
+	}
+
+	// references, once per referenced object
+	references := model.references()
+	for _, id := range slices.Sorted(maps.Keys(model.referenceProblems)) {
+		finding("reference", nil, "%s '%s' %s", referenceKinds[references[id]], path.Base(id), model.referenceProblems[id])
+	}
+
+	// address family of the IP prefixes
+	if addressFamily == nil {
+		finding("addressFamily", nil, "addressFamilyType is not set, so address family mismatches cannot be checked")
+	} else {
+		for _, id := range slices.Sorted(maps.Keys(model.prefixes)) {
+			for _, rule := range model.prefixes[id].IPPrefixRules {
+				if rule == nil || rule.NetworkPrefix == nil {
+					continue
+				}
+				prefix, err := netip.ParsePrefix(*rule.NetworkPrefix)
+				if err != nil {
+					continue
+				}
+				if family := addressFamilyOf(prefix); family != *addressFamily {
+					finding("addressFamily", nil, "IP prefix '%s' matches %s prefix %s, but the route policy is %s", path.Base(id), family, *rule.NetworkPrefix, *addressFamily)
+				}
+			}
+		}
+	}
+
+	// reachability, in evaluation order
+	ordered := model.orderedStatements()
+	defaultFound := false
+	for j, statement := range ordered {
+		sequenceNumber := statement.SequenceNumber
+		result := StatementAnalysis{
+			SequenceNumber: *sequenceNumber,
+			Condition:      model.describeCondition(statement.Condition),
+			Action:         describeAction(statement.Action),
+			Reachable:      true,
+		}
+
+		if statement.Action == nil || statement.Action.ActionType == nil {
+			finding("condition", sequenceNumber, "statement %d has no action type", *sequenceNumber)
+		}
+		if statement.Condition != nil && statement.Condition.Type != nil && !slices.Contains(armmanagednetworkfabric.PossibleRoutePolicyConditionTypeValues(), *statement.Condition.Type) {
+			finding("condition", sequenceNumber, "statement %d has condition type '%s', expected And or Or", *sequenceNumber, *statement.Condition.Type)
+		}
+
+		for _, earlier := range ordered[:j] {
+			if !terminates(earlier) || *earlier.SequenceNumber == *sequenceNumber {
+				continue
+			}
+			if model.conditionCovers(earlier.Condition, statement.Condition) {
+				result.Reachable = false
+				result.ShadowedBy = earlier.SequenceNumber
+				finding("shadowed", sequenceNumber, "statement %d can never match: statement %d (%s) matches every route it does and is evaluated first", *sequenceNumber, *earlier.SequenceNumber, strings.ToLower(string(*earlier.Action.ActionType)))
+				break
+			}
+		}
+
+		if result.Reachable && !defaultFound && terminates(statement) && len(conditionCriteria(statement.Condition)) == 0 {
+			defaultFound = true
+			analysis.DefaultAction = string(*statement.Action.ActionType)
+			analysis.DefaultActionSource = fmt.Sprintf("statement %d, which matches every route", *sequenceNumber)
+		}
+
+		analysis.Statements = append(analysis.Statements, result)
+	}
+
+	return analysis
+}
+
+// String renders the analysis for the text result.
+func (analysis RoutePolicyAnalysis) String() string {
+	result := fmt.Sprintf("Route policy %s (%s)\n", analysis.RoutePolicyID, analysis.AddressFamilyType)
+	result += "\nStatements in evaluation order:\n"
+	for _, statement := range analysis.Statements {
+		line := fmt.Sprintf("- %d: if %s then %s", statement.SequenceNumber, statement.Condition, statement.Action)
+		if !statement.Reachable {
+			line += fmt.Sprintf(" (unreachable, shadowed by %d)", *statement.ShadowedBy)
+		}
+		result += line + "\n"
+	}
+	result += fmt.Sprintf("\nDefault action: %s, from %s.\n", analysis.DefaultAction, analysis.DefaultActionSource)
+
+	if len(analysis.Findings) == 0 {
+		return result + "\nNo problems found."
+	}
+	result += fmt.Sprintf("\n%d finding(s):\n", len(analysis.Findings))
+	for _, finding := range analysis.Findings {
+		result += fmt.Sprintf("- [%s] %s\n", finding.Kind, finding.Message)
+	}
+	return result
+}
+
+// terminates reports whether routes matching the statement stop there. Continue passes them on to
+// the next statement.
+func terminates(statement *armmanagednetworkfabric.RoutePolicyStatementProperties) bool {
+	return statement.Action != nil && statement.Action.ActionType != nil && *statement.Action.ActionType != armmanagednetworkfabric.RoutePolicyActionTypeContinue
+}
+
+func conditionCriteria(condition *armmanagednetworkfabric.StatementConditionProperties) []matchCriterion {
+	if condition == nil {
+		return nil
+	}
+
+	lower := func(ids ...*string) []string {
+		values := []string{}
+		for _, id := range ids {
+			if id != nil && *id != "" {
+				values = append(values, strings.ToLower(*id))
+			}
+		}
+		return values
+	}
+
+	criteria := []matchCriterion{}
+	if ids := lower(condition.IPPrefixID); len(ids) > 0 {
+		criteria = append(criteria, matchCriterion{resourceType: IP_PREFIX_RESOURCE_TYPE, ids: ids})
+	}
+	if ids := lower(condition.IPCommunityIDs...); len(ids) > 0 {
+		criteria = append(criteria, matchCriterion{resourceType: IP_COMMUNITY_RESOURCE_TYPE, ids: ids})
+	}
+	if ids := lower(condition.IPExtendedCommunityIDs...); len(ids) > 0 {
+		criteria = append(criteria, matchCriterion{resourceType: IP_EXT_COMMUNITY_RESOURCE_TYPE, ids: ids})
+	}
+	return criteria
+}
+
+// isOr reports whether a route has to match any criterion of the condition rather than all of them.
+// Like the match clauses of a route map, criteria are combined with And unless the type says Or.
+func isOr(condition *armmanagednetworkfabric.StatementConditionProperties) bool {
+	return condition != nil && condition.Type != nil && *condition.Type == armmanagednetworkfabric.RoutePolicyConditionTypeOr
+}
+
+// conditionCovers reports whether every route matching inner also matches outer. It errs on the side
+// of false, so a statement is only reported as shadowed when it certainly is.
+func (model *routePolicyModel) conditionCovers(outer, inner *armmanagednetworkfabric.StatementConditionProperties) bool {
+	outerCriteria, innerCriteria := conditionCriteria(outer), conditionCriteria(inner)
+	if len(outerCriteria) == 0 {
+		return true
+	}
+	if len(innerCriteria) == 0 {
+		return false
+	}
+
+	anyCovers := func(outerCriterion matchCriterion, criteria []matchCriterion) bool {
+		return slices.ContainsFunc(criteria, func(innerCriterion matchCriterion) bool {
+			return model.criterionCovers(outerCriterion, innerCriterion)
+		})
+	}
+	allCover := func(outerCriterion matchCriterion, criteria []matchCriterion) bool {
+		for _, innerCriterion := range criteria {
+			if !model.criterionCovers(outerCriterion, innerCriterion) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case !isOr(outer) && !isOr(inner):
+		// every outer criterion is implied by one of the inner ones
+		for _, outerCriterion := range outerCriteria {
+			if !anyCovers(outerCriterion, innerCriteria) {
+				return false
+			}
+		}
+		return true
+	case !isOr(outer):
+		// every inner alternative satisfies every outer criterion
+		for _, outerCriterion := range outerCriteria {
+			if !allCover(outerCriterion, innerCriteria) {
+				return false
+			}
+		}
+		return true
+	case !isOr(inner):
+		// one outer alternative is implied by one of the inner criteria
+		for _, outerCriterion := range outerCriteria {
+			if anyCovers(outerCriterion, innerCriteria) {
+				return true
+			}
+		}
+		return false
+	default:
+		// every inner alternative is within one of the outer ones
+		for _, innerCriterion := range innerCriteria {
+			if !slices.ContainsFunc(outerCriteria, func(outerCriterion matchCriterion) bool {
+				return model.criterionCovers(outerCriterion, innerCriterion)
+			}) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// criterionCovers reports whether every route matching inner also matches outer. A community
+// criterion matches routes carrying any of its communities.
+func (model *routePolicyModel) criterionCovers(outer, inner matchCriterion) bool {
+	if outer.resourceType != inner.resourceType {
+		return false
+	}
+	if outer.resourceType != IP_PREFIX_RESOURCE_TYPE {
+		for _, id := range inner.ids {
+			if !slices.Contains(outer.ids, id) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if outer.ids[0] == inner.ids[0] {
+		return true
+	}
+	outerMatches, outerExact := model.prefixMatches(outer.ids[0])
+	innerMatches, _ := model.prefixMatches(inner.ids[0])
+	// deny rules carve holes into the outer prefix that are not worth modelling
+	if !outerExact || len(innerMatches) == 0 {
+		return false
+	}
+	for _, innerMatch := range innerMatches {
+		if !slices.ContainsFunc(outerMatches, innerMatch.within) {
+			return false
+		}
+	}
+	return true
+}
+
+// prefixMatches returns what the permit rules of an IP prefix match. exact is false when the IP
+// prefix is unknown, or has deny rules or rules that cannot be parsed, so the permit rules
+// overstate what it matches.
+func (model *routePolicyModel) prefixMatches(id string) (matches []prefixMatch, exact bool) {
+	properties, ok := model.prefixes[id]
+	if !ok {
+		return nil, false
+	}
+
+	exact = true
+	for _, rule := range properties.IPPrefixRules {
+		if rule == nil || rule.Action == nil || *rule.Action != armmanagednetworkfabric.CommunityActionTypesPermit {
+			exact = false
+			continue
+		}
+		match, ok := newPrefixMatch(rule)
+		if !ok {
+			exact = false
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, exact
+}
+
+func newPrefixMatch(rule *armmanagednetworkfabric.IPPrefixRule) (prefixMatch, bool) {
+	if rule.NetworkPrefix == nil {
+		return prefixMatch{}, false
+	}
+	prefix, err := netip.ParsePrefix(*rule.NetworkPrefix)
+	if err != nil {
+		return prefixMatch{}, false
+	}
+	prefix = prefix.Masked()
+
+	match := prefixMatch{prefix: prefix, low: prefix.Bits(), high: prefix.Bits()}
+	if rule.Condition == nil || rule.SubnetMaskLength == nil || *rule.SubnetMaskLength == "" {
+		return match, true
+	}
+
+	if *rule.Condition == armmanagednetworkfabric.ConditionRange {
+		low, high, ok := strings.Cut(*rule.SubnetMaskLength, "-")
+		lowLength, lowErr := strconv.Atoi(strings.TrimSpace(low))
+		highLength, highErr := strconv.Atoi(strings.TrimSpace(high))
+		if !ok || lowErr != nil || highErr != nil {
+			return prefixMatch{}, false
+		}
+		match.low, match.high = lowLength, highLength
+		return match, true
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(*rule.SubnetMaskLength))
+	if err != nil {
+		return prefixMatch{}, false
+	}
+	switch *rule.Condition {
+	case armmanagednetworkfabric.ConditionEqualTo:
+		match.low, match.high = length, length
+	case armmanagednetworkfabric.ConditionGreaterThanOrEqualTo:
+		match.low, match.high = length, prefix.Addr().BitLen()
+	case armmanagednetworkfabric.ConditionLesserThanOrEqualTo:
+		match.high = length
+	}
+	return match, true
+}
+
+// within reports whether every route matched by match is also matched by outer.
+func (match prefixMatch) within(outer prefixMatch) bool {
+	return outer.prefix.Addr().BitLen() == match.prefix.Addr().BitLen() &&
+		outer.prefix.Bits() <= match.prefix.Bits() &&
+		outer.prefix.Contains(match.prefix.Addr()) &&
+		outer.low <= match.low && match.high <= outer.high
+}
+
+func (model *routePolicyModel) describeCondition(condition *armmanagednetworkfabric.StatementConditionProperties) string {
+	criteria := conditionCriteria(condition)
+	if len(criteria) == 0 {
+		return "any route"
+	}
+
+	parts := make([]string, 0, len(criteria))
+	for _, criterion := range criteria {
+		resources := make([]string, 0, len(criterion.ids))
+		for _, id := range criterion.ids {
+			resources = append(resources, path.Base(id))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", referenceKinds[criterion.resourceType], strings.Join(resources, " or ")))
+	}
+
+	if isOr(condition) {
+		return strings.Join(parts, " or ")
+	}
+	return strings.Join(parts, " and ")
+}
+
+func describeAction(action *armmanagednetworkfabric.StatementActionProperties) string {
+	if action == nil || action.ActionType == nil {
+		return "no action"
+	}
+	description := strings.ToLower(string(*action.ActionType))
+	if action.LocalPreference != nil {
+		description += fmt.Sprintf(" with local preference %d", *action.LocalPreference)
+	}
+	return description
+}
+
+func addressFamilyOf(prefix netip.Prefix) armmanagednetworkfabric.AddressFamilyType {
+	if prefix.Addr().Is4() {
+		return armmanagednetworkfabric.AddressFamilyTypeIPv4
+	}
+	return armmanagednetworkfabric.AddressFamilyTypeIPv6
+}
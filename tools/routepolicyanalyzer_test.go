@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+)
+
+func TestNewPrefixMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      *armmanagednetworkfabric.IPPrefixRule
+		prefix    string
+		low, high int
+		ok        bool
+	}{
+		{"exact", testPrefixRule("Permit", "10.2.0.0/16", "", ""), "10.2.0.0/16", 16, 16, true},
+		{"masked", testPrefixRule("Permit", "10.2.3.4/16", "", ""), "10.2.0.0/16", 16, 16, true},
+		{"equal to", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionEqualTo, "24"), "10.2.0.0/16", 24, 24, true},
+		{"greater than or equal to", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "24"), "10.2.0.0/16", 24, 32, true},
+		{"greater than or equal to IPv6", testPrefixRule("Permit", "fd00::/48", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "64"), "fd00::/48", 64, 128, true},
+		{"lesser than or equal to", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionLesserThanOrEqualTo, "20"), "10.2.0.0/16", 16, 20, true},
+		{"range", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionRange, "24-28"), "10.2.0.0/16", 24, 28, true},
+		{"range with spaces", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionRange, "24 - 28"), "10.2.0.0/16", 24, 28, true},
+		{"invalid range", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionRange, "24"), "", 0, 0, false},
+		{"invalid length", testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "x"), "", 0, 0, false},
+		{"invalid prefix", testPrefixRule("Permit", "10.2.0.0", "", ""), "", 0, 0, false},
+		{"no prefix", &armmanagednetworkfabric.IPPrefixRule{}, "", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match, ok := newPrefixMatch(test.rule)
+			if ok != test.ok {
+				t.Fatalf("ok = %v, want %v", ok, test.ok)
+			}
+			if !ok {
+				return
+			}
+			if want := netip.MustParsePrefix(test.prefix); match.prefix != want || match.low != test.low || match.high != test.high {
+				t.Errorf("match = %s %d-%d, want %s %d-%d", match.prefix, match.low, match.high, want, test.low, test.high)
+			}
+		})
+	}
+}
+
+func TestPrefixMatchWithin(t *testing.T) {
+	match := func(prefix string, low, high int) prefixMatch {
+		return prefixMatch{prefix: netip.MustParsePrefix(prefix), low: low, high: high}
+	}
+
+	tests := []struct {
+		name         string
+		inner, outer prefixMatch
+		want         bool
+	}{
+		{"same", match("10.2.0.0/16", 16, 16), match("10.2.0.0/16", 16, 16), true},
+		{"more specific within ge", match("10.2.3.0/24", 24, 24), match("10.2.0.0/16", 24, 32), true},
+		{"more specific outside ge", match("10.2.3.0/24", 24, 24), match("10.2.0.0/16", 25, 32), false},
+		{"exact outside ge", match("10.2.0.0/16", 16, 16), match("10.2.0.0/16", 24, 32), false},
+		{"range within range", match("10.2.3.0/24", 25, 27), match("10.2.0.0/16", 24, 28), true},
+		{"range past range", match("10.2.3.0/24", 25, 30), match("10.2.0.0/16", 24, 28), false},
+		{"other network", match("10.3.0.0/24", 24, 24), match("10.2.0.0/16", 16, 32), false},
+		{"less specific", match("10.0.0.0/8", 16, 16), match("10.2.0.0/16", 16, 16), false},
+		{"other family", match("fd00::/64", 64, 64), match("0.0.0.0/0", 0, 128), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.inner.within(test.outer); got != test.want {
+				t.Errorf("within = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConditionCovers(t *testing.T) {
+	model := testRoutePolicyModel(map[string][]*armmanagednetworkfabric.IPPrefixRule{
+		"wide":    {testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "24")},
+		"narrow":  {testPrefixRule("Permit", "10.2.3.0/24", "", "")},
+		"short":   {testPrefixRule("Permit", "10.2.0.0/16", "", "")},
+		"outside": {testPrefixRule("Permit", "10.3.3.0/24", "", "")},
+		"holes": {
+			testPrefixRule("Deny", "10.2.3.0/24", "", ""),
+			testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "24"),
+		},
+	})
+
+	and, or := armmanagednetworkfabric.RoutePolicyConditionTypeAnd, armmanagednetworkfabric.RoutePolicyConditionTypeOr
+	tests := []struct {
+		name         string
+		outer, inner *armmanagednetworkfabric.StatementConditionProperties
+		want         bool
+	}{
+		{"no outer condition", nil, testPrefixCondition("narrow"), true},
+		{"no inner condition", testPrefixCondition("wide"), nil, false},
+		{"same prefix", testPrefixCondition("narrow"), testPrefixCondition("narrow"), true},
+		{"prefix within", testPrefixCondition("wide"), testPrefixCondition("narrow"), true},
+		{"prefix shorter than ge", testPrefixCondition("wide"), testPrefixCondition("short"), false},
+		{"prefix outside", testPrefixCondition("wide"), testPrefixCondition("outside"), false},
+		{"deny rules are not modelled", testPrefixCondition("holes"), testPrefixCondition("narrow"), false},
+		{"unknown prefix", testPrefixCondition("missing"), testPrefixCondition("narrow"), false},
+		{"community subset", testCommunityCondition(and, "", "a", "b"), testCommunityCondition(and, "", "a"), true},
+		{"community superset", testCommunityCondition(and, "", "a"), testCommunityCondition(and, "", "a", "b"), false},
+		{"and with more criteria", testCommunityCondition(and, "wide"), testCommunityCondition(and, "narrow", "a"), true},
+		{"and with fewer criteria", testCommunityCondition(and, "wide", "a"), testCommunityCondition(and, "narrow"), false},
+		{"and over or alternatives", testCommunityCondition(and, "wide"), testCommunityCondition(or, "narrow", "a"), false},
+		{"or alternative implied", testCommunityCondition(or, "wide", "b"), testCommunityCondition(and, "narrow", "a"), true},
+		{"or alternatives within", testCommunityCondition(or, "wide", "a"), testCommunityCondition(or, "narrow", "a"), true},
+		{"or alternative outside", testCommunityCondition(or, "wide", "a"), testCommunityCondition(or, "outside", "a"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := model.conditionCovers(test.outer, test.inner); got != test.want {
+				t.Errorf("conditionCovers = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeShadowing(t *testing.T) {
+	prefixes := map[string][]*armmanagednetworkfabric.IPPrefixRule{
+		"wide":   {testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "24")},
+		"narrow": {testPrefixRule("Permit", "10.2.3.0/24", "", "")},
+	}
+	permit, deny, next := armmanagednetworkfabric.RoutePolicyActionTypePermit, armmanagednetworkfabric.RoutePolicyActionTypeDeny, armmanagednetworkfabric.RoutePolicyActionTypeContinue
+
+	tests := []struct {
+		name          string
+		statements    []*armmanagednetworkfabric.RoutePolicyStatementProperties
+		prefixes      map[string][]*armmanagednetworkfabric.IPPrefixRule
+		shadowedBy    map[int64]int64
+		defaultAction string
+		findings      []string
+	}{
+		{
+			name: "wider statement first",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(10, permit, testPrefixCondition("wide")),
+				testStatement(20, deny, testPrefixCondition("narrow")),
+			},
+			shadowedBy:    map[int64]int64{20: 10},
+			defaultAction: "Deny",
+			findings:      []string{"shadowed"},
+		},
+		{
+			name: "narrower statement first",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(10, deny, testPrefixCondition("narrow")),
+				testStatement(20, permit, testPrefixCondition("wide")),
+			},
+			shadowedBy:    map[int64]int64{},
+			defaultAction: "Deny",
+		},
+		{
+			name: "continue does not shadow",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(10, next, testPrefixCondition("wide")),
+				testStatement(20, permit, testPrefixCondition("narrow")),
+			},
+			shadowedBy:    map[int64]int64{},
+			defaultAction: "Deny",
+		},
+		{
+			name: "evaluated by sequence number",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(20, deny, testPrefixCondition("narrow")),
+				testStatement(10, permit, testPrefixCondition("wide")),
+			},
+			shadowedBy:    map[int64]int64{20: 10},
+			defaultAction: "Deny",
+			findings:      []string{"sequence", "shadowed"},
+		},
+		{
+			name: "catch all sets the default action",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(10, permit, nil),
+				testStatement(20, deny, testPrefixCondition("narrow")),
+			},
+			shadowedBy:    map[int64]int64{20: 10},
+			defaultAction: "Permit",
+			findings:      []string{"shadowed"},
+		},
+		{
+			name: "duplicate sequence number and address family",
+			statements: []*armmanagednetworkfabric.RoutePolicyStatementProperties{
+				testStatement(10, permit, testPrefixCondition("v6")),
+				testStatement(10, deny, testPrefixCondition("narrow")),
+			},
+			prefixes: map[string][]*armmanagednetworkfabric.IPPrefixRule{
+				"narrow": {testPrefixRule("Permit", "10.2.3.0/24", "", "")},
+				"v6":     {testPrefixRule("Permit", "fd00::/48", "", "")},
+			},
+			shadowedBy:    map[int64]int64{},
+			defaultAction: "Deny",
+			findings:      []string{"sequence", "addressFamily"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.prefixes == nil {
+				test.prefixes = prefixes
+			}
+			analysis := testRoutePolicyModel(test.prefixes, test.statements...).analyze()
+
+			for _, statement := range analysis.Statements {
+				want, shadowed := test.shadowedBy[statement.SequenceNumber]
+				if statement.Reachable == shadowed {
+					t.Errorf("statement %d reachable = %v, want %v", statement.SequenceNumber, statement.Reachable, !shadowed)
+				}
+				if shadowed && (statement.ShadowedBy == nil || *statement.ShadowedBy != want) {
+					t.Errorf("statement %d shadowed by %v, want %d", statement.SequenceNumber, statement.ShadowedBy, want)
+				}
+			}
+			if analysis.DefaultAction != test.defaultAction {
+				t.Errorf("default action = %s, want %s", analysis.DefaultAction, test.defaultAction)
+			}
+
+			kinds := []string{}
+			for _, finding := range analysis.Findings {
+				kinds = append(kinds, finding.Kind)
+			}
+			if strings.Join(kinds, ",") != strings.Join(test.findings, ",") {
+				t.Errorf("findings = %v, want %v", analysis.Findings, test.findings)
+			}
+		})
+	}
+}
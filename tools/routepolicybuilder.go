@@ -0,0 +1,693 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Sequence numbers of the generated statements and rules are spaced out, so entries can be inserted
+// between them later.
+const BUILD_SEQUENCE_STEP = 10
+
+const (
+	BUILD_STATUS_PLANNED = "planned"
+	BUILD_STATUS_CREATED = "created"
+)
+
+// RoutePolicyIntent describes a route policy by what it should do. build_routepolicy turns it into
+// the IP prefixes, IP communities, IP extended communities and statements ARM expects.
+type RoutePolicyIntent struct {
+	AddressFamilyType string            `json:"addressFamilyType,omitempty" jsonschema:"enum=IPv4,enum=IPv6" jsonschema_description:"The address family of the routes. Defaults to the family of the prefixes."`
+	Statements        []StatementIntent `json:"statements" jsonschema:"required" jsonschema_description:"The statements, in the order they are evaluated."`
+	DefaultAction     string            `json:"defaultAction,omitempty" jsonschema:"enum=Permit,enum=Deny" jsonschema_description:"What happens to the routes no statement permits or denies. Defaults to Deny."`
+}
+
+type StatementIntent struct {
+	Action             string   `json:"action" jsonschema:"required,enum=Permit,enum=Deny,enum=Continue"`
+	Prefixes           []string `json:"prefixes,omitempty" jsonschema_description:"Match routes for these prefixes, e.g. 10.2.0.0/16, or for the more specific prefixes with 10.2.0.0/16 ge 24, 10.2.0.0/16 le 24 or 10.2.0.0/16 24-28."`
+	Communities        []string `json:"communities,omitempty" jsonschema_description:"Match routes carrying any of these communities, e.g. 65001:100 or NoExport."`
+	RouteTargets       []string `json:"routeTargets,omitempty" jsonschema_description:"Match routes carrying any of these route targets, e.g. 65001:100."`
+	AddCommunities     []string `json:"addCommunities,omitempty" jsonschema_description:"Tag the matching routes with these communities."`
+	SetCommunities     []string `json:"setCommunities,omitempty" jsonschema_description:"Replace the communities of the matching routes."`
+	DeleteCommunities  []string `json:"deleteCommunities,omitempty" jsonschema_description:"Remove these communities from the matching routes."`
+	AddRouteTargets    []string `json:"addRouteTargets,omitempty" jsonschema_description:"Tag the matching routes with these route targets."`
+	SetRouteTargets    []string `json:"setRouteTargets,omitempty" jsonschema_description:"Replace the route targets of the matching routes."`
+	DeleteRouteTargets []string `json:"deleteRouteTargets,omitempty" jsonschema_description:"Remove these route targets from the matching routes."`
+	LocalPreference    *int64   `json:"localPreference,omitempty" jsonschema_description:"Set the local preference of the matching routes."`
+}
+
+// buildResource is a resource build_routepolicy creates, with the properties sent to ARM.
+type buildResource struct {
+	target     TargetResource
+	properties any
+}
+
+func BuildRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return buildRoutePolicy(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		name, ok := args["name"].(string)
+		if !ok || name == "" {
+			return nil, errors.New("route policy name missing")
+		}
+
+		location, ok := args["location"].(string)
+		if !ok || location == "" {
+			return nil, errors.New("location missing")
+		}
+
+		var intent RoutePolicyIntent
+		if err := parseObjectArgument(args, "intent", &intent); err != nil {
+			return nil, err
+		}
+
+		resourceGroupName, ok := args["resourceGroupName"].(string)
+		if !ok || resourceGroupName == "" {
+			return nil, errors.New("resource group name missing")
+		}
+
+		subscriptionId, ok := args["subscriptionId"].(string)
+		if !ok || subscriptionId == "" {
+			return nil, errors.New("subscription id missing")
+		}
+
+		fabricId, _ := args["networkFabricId"].(string)
+		if fabricId == "" {
+			fabricName, _ := args["fabricName"].(string)
+			if fabricName == "" {
+				return nil, errors.New("network fabric missing")
+			}
+			fabricId = TargetResource{
+				SubscriptionID: subscriptionId,
+				ResourceGroup:  resourceGroupName,
+				ResourceType:   NETWORK_FABRIC_RESOURCE_TYPE,
+				Name:           fabricName,
+			}.ID()
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		fabric := struct {
+			NetworkFabricID string `json:"networkFabricId"`
+		}{fabricId}
+		policyTarget := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   ROUTE_POLICY_RESOURCE_TYPE,
+			Name:           name,
+		}
+		if result, err := resolveReferences(ctx, clientRetriever, cred, &policyTarget, &fabric); result != nil || err != nil {
+			return result, err
+		}
+
+		resources, violations := planRoutePolicyBuild(subscriptionId, resourceGroupName, name, fabric.NetworkFabricID, intent)
+		if len(violations) > 0 {
+			return validationErrorResult("route policy intent", violations), nil
+		}
+
+		for _, resource := range resources {
+			if result, err := checkProtection(ctx, clientRetriever, cred, OPERATION_CREATE, resource.target); result != nil || err != nil {
+				return result, err
+			}
+		}
+
+		client, err := armresources.NewClient(subscriptionId, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resources client: %v", err)
+		}
+
+		build := RoutePolicyBuild{
+			RoutePolicyID: resources[len(resources)-1].target.ID(),
+			Resources:     make([]BuiltResource, 0, len(resources)),
+		}
+		existing := []string{}
+		for _, resource := range resources {
+			_, exists, err := getGenericResource(ctx, client, resource.target.ID())
+			if err != nil {
+				return armErrorResult(fmt.Sprintf("failed to check whether %s '%s' exists", resource.target.ResourceType, resource.target.Name), err)
+			}
+			if exists {
+				existing = append(existing, fmt.Sprintf("%s '%s'", resource.target.ResourceType, resource.target.Name))
+			}
+
+			builtResource, err := newBuiltResource(resource)
+			if err != nil {
+				return nil, err
+			}
+			build.Resources = append(build.Resources, builtResource)
+		}
+
+		if dryRun, _ := args["dryRun"].(bool); dryRun {
+			build.DryRun = true
+			for _, resource := range existing {
+				build.Warnings = append(build.Warnings, fmt.Sprintf("%s already exists, so the build would be refused.", resource))
+			}
+			build.Message = fmt.Sprintf("Dry run: would create %d resources for Route Policy '%s', the route policy last. No changes were made.", len(resources), name)
+			return mcp.NewToolResultStructured(build, build.String()), nil
+		}
+
+		if len(existing) > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Refusing to build Route Policy '%s', nothing was created. These resources already exist:\n- %s\nDelete them or choose another route policy name.", name, strings.Join(existing, "\n- "))), nil
+		}
+
+		if result, err := confirmAction(ctx, clientRetriever, fmt.Sprintf("Build Route Policy '%s' in resource group '%s'", name, resourceGroupName), routePolicyBuildImpact(resources)); result != nil || err != nil {
+			return result, err
+		}
+
+		for i, resource := range resources {
+			if err := createBuildResource(ctx, clientRetriever, cred, location, resource); err != nil {
+				return rollbackRoutePolicyBuild(ctx, clientRetriever, cred, resources[:i+1], err), nil
+			}
+			build.Resources[i].Status = BUILD_STATUS_CREATED
+		}
+
+		build.Message = fmt.Sprintf("Route Policy '%s' built in resource group '%s' with %d IP prefixes, IP communities and IP extended communities", name, resourceGroupName, len(resources)-1)
+		return mcp.NewToolResultStructured(build, build.String()), nil
+	}
+}
+
+func buildRoutePolicy() mcp.Tool {
+	return mcp.NewTool(
+		BUILD_ROUTE_POLICY_TOOL_NAME,
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
+		),
+		mcp.WithObject("intent",
+			mcp.Required(),
+			mcp.Description(ROUTE_POLICY_INTENT_DESCRIPTION),
+			mcp.Properties(propertiesSchema[RoutePolicyIntent]()),
+		),
+		mcp.WithString("networkFabricId",
+			mcp.Description(ROUTE_POLICY_FABRIC_ID_DESCRIPTION),
+		),
+		mcp.WithString("fabricName",
+			mcp.Description(NETWORK_FABRIC_PARAMETER_DESCRIPTION),
+		),
+		mcp.WithString("location",
+			mcp.Description(ROUTE_POLICY_LOCATION_DESCRIPTION),
+		),
+		mcp.WithString("resourceGroupName",
+			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
+		),
+		mcp.WithString("subscriptionId",
+			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description(DRY_RUN_DESCRIPTION),
+		),
+		mcp.WithOutputSchema[RoutePolicyBuild](),
+		mcp.WithDescription("Build a Route Policy from intent, e.g. permit these prefixes, tag them with community 65001:100 and deny everything else. Creates the IP prefixes, IP communities and IP extended communities the statements need, named after the route policy and its statement sequence numbers, then the Route Policy referencing them. The user is asked to confirm the resources to create first. Everything created is deleted again if a later step fails."),
+	)
+}
+
+// planRoutePolicyBuild turns the intent into the resources to create in dependency order, the route
+// policy last. Statement and rule sequence numbers are numbered in steps of BUILD_SEQUENCE_STEP in the
+// order given, and the objects of a statement are named after the policy and its sequence number,
+// e.g. rp1-prefix-10 and rp1-community-10-add.
+func planRoutePolicyBuild(subscriptionId, resourceGroupName, name, fabricId string, intent RoutePolicyIntent) ([]buildResource, []string) {
+	resources := []buildResource{}
+	violations := []string{}
+	annotation := fmt.Sprintf("Built for route policy '%s'", name)
+
+	add := func(resourceType, resourceName string, properties any) *string {
+		target := TargetResource{
+			SubscriptionID: subscriptionId,
+			ResourceGroup:  resourceGroupName,
+			ResourceType:   resourceType,
+			Name:           resourceName,
+		}
+		resources = append(resources, buildResource{target: target, properties: properties})
+		return to.Ptr(target.ID())
+	}
+	violate := func(field string, found []string) {
+		for _, violation := range found {
+			violations = append(violations, fmt.Sprintf("%s: %s", field, violation))
+		}
+	}
+
+	family, familyOk := addressFamily(intent.AddressFamilyType, intent.Statements)
+	if !familyOk {
+		violations = append(violations, fmt.Sprintf("addressFamilyType '%s' is not one of %s", intent.AddressFamilyType, strings.Join(enumValues(armmanagednetworkfabric.PossibleAddressFamilyTypeValues()), ", ")))
+	}
+	var versions []string
+	if family != "" {
+		versions = []string{strings.ToLower(string(family))}
+	}
+
+	addPrefix := func(field, resourceName string, prefixes []string) *string {
+		rules := []*armmanagednetworkfabric.IPPrefixRule{}
+		for j, prefix := range prefixes {
+			rule, err := parsePrefixIntent(prefix)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%s[%d]: %v", field, j, err))
+				continue
+			}
+			rule.SequenceNumber = to.Ptr(int64((j + 1) * BUILD_SEQUENCE_STEP))
+			rule.Action = to.Ptr(armmanagednetworkfabric.CommunityActionTypesPermit)
+			rules = append(rules, rule)
+		}
+		violate(field, validateIPPrefixRules(rules, versions))
+		return add(IP_PREFIX_RESOURCE_TYPE, resourceName, &armmanagednetworkfabric.IPPrefixProperties{
+			Annotation:    &annotation,
+			IPPrefixRules: rules,
+		})
+	}
+
+	// one permit rule per community, so that routes carrying any of them match
+	addCommunity := func(field, resourceName string, communities []string) []*string {
+		rules := []*armmanagednetworkfabric.IPCommunityRule{}
+		for j, community := range communities {
+			rule := &armmanagednetworkfabric.IPCommunityRule{
+				Action:           to.Ptr(armmanagednetworkfabric.CommunityActionTypesPermit),
+				SequenceNumber:   to.Ptr(int64((j + 1) * BUILD_SEQUENCE_STEP)),
+				CommunityMembers: []*string{},
+			}
+			if wellKnown, ok := wellKnownCommunity(community); ok {
+				rule.WellKnownCommunities = []*armmanagednetworkfabric.WellKnownCommunities{&wellKnown}
+			} else {
+				rule.CommunityMembers = append(rule.CommunityMembers, to.Ptr(strings.TrimSpace(community)))
+			}
+			rules = append(rules, rule)
+		}
+		violate(field, validateIPCommunityRules(rules))
+		return []*string{add(IP_COMMUNITY_RESOURCE_TYPE, resourceName, &armmanagednetworkfabric.IPCommunityProperties{
+			Annotation:       &annotation,
+			IPCommunityRules: rules,
+		})}
+	}
+
+	addRouteTargets := func(field, resourceName string, routeTargets []string) []*string {
+		rules := []*armmanagednetworkfabric.IPExtendedCommunityRule{}
+		for j, routeTarget := range routeTargets {
+			rules = append(rules, &armmanagednetworkfabric.IPExtendedCommunityRule{
+				Action:         to.Ptr(armmanagednetworkfabric.CommunityActionTypesPermit),
+				SequenceNumber: to.Ptr(int64((j + 1) * BUILD_SEQUENCE_STEP)),
+				RouteTargets:   []*string{to.Ptr(strings.TrimSpace(routeTarget))},
+			})
+		}
+		violate(field, validateIPExtCommunityRules(rules))
+		return []*string{add(IP_EXT_COMMUNITY_RESOURCE_TYPE, resourceName, &armmanagednetworkfabric.IPExtendedCommunityProperties{
+			Annotation:               &annotation,
+			IPExtendedCommunityRules: rules,
+		})}
+	}
+
+	if len(intent.Statements) == 0 {
+		violations = append(violations, "statements: at least one statement is needed")
+	}
+
+	statements := []*armmanagednetworkfabric.RoutePolicyStatementProperties{}
+	for i, statementIntent := range intent.Statements {
+		field := fmt.Sprintf("statements[%d]", i)
+		sequenceNumber := int64((i + 1) * BUILD_SEQUENCE_STEP)
+		objectName := func(kind, suffix string) string {
+			return strings.TrimSuffix(fmt.Sprintf("%s-%s-%d-%s", name, kind, sequenceNumber, suffix), "-")
+		}
+
+		actionType, ok := routePolicyActionType(statementIntent.Action)
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%s.action '%s' is not one of %s", field, statementIntent.Action, strings.Join(enumValues(armmanagednetworkfabric.PossibleRoutePolicyActionTypeValues()), ", ")))
+		}
+		if len(statementIntent.Prefixes) == 0 && len(statementIntent.Communities) == 0 && len(statementIntent.RouteTargets) == 0 {
+			violations = append(violations, fmt.Sprintf("%s matches nothing, give prefixes, communities or routeTargets. Use 0.0.0.0/0 le 32 or ::/0 le 128 to match every route", field))
+		}
+
+		condition := &armmanagednetworkfabric.StatementConditionProperties{
+			Type: to.Ptr(armmanagednetworkfabric.RoutePolicyConditionTypeAnd),
+		}
+		if len(statementIntent.Prefixes) > 0 {
+			condition.IPPrefixID = addPrefix(field+".prefixes", objectName("prefix", ""), statementIntent.Prefixes)
+		}
+		if len(statementIntent.Communities) > 0 {
+			condition.IPCommunityIDs = addCommunity(field+".communities", objectName("community", ""), statementIntent.Communities)
+		}
+		if len(statementIntent.RouteTargets) > 0 {
+			condition.IPExtendedCommunityIDs = addRouteTargets(field+".routeTargets", objectName("routetarget", ""), statementIntent.RouteTargets)
+		}
+
+		action := &armmanagednetworkfabric.StatementActionProperties{
+			ActionType:      &actionType,
+			LocalPreference: statementIntent.LocalPreference,
+		}
+		communityLists := map[string]*armmanagednetworkfabric.IPCommunityIDList{}
+		routeTargetLists := map[string]*armmanagednetworkfabric.IPExtendedCommunityIDList{}
+		for _, modification := range []struct {
+			suffix                    string
+			communities, routeTargets []string
+		}{
+			{"set", statementIntent.SetCommunities, statementIntent.SetRouteTargets},
+			{"delete", statementIntent.DeleteCommunities, statementIntent.DeleteRouteTargets},
+			{"add", statementIntent.AddCommunities, statementIntent.AddRouteTargets},
+		} {
+			if len(modification.communities) > 0 {
+				communityLists[modification.suffix] = &armmanagednetworkfabric.IPCommunityIDList{
+					IPCommunityIDs: addCommunity(fmt.Sprintf("%s.%sCommunities", field, modification.suffix), objectName("community", modification.suffix), modification.communities),
+				}
+			}
+			if len(modification.routeTargets) > 0 {
+				routeTargetLists[modification.suffix] = &armmanagednetworkfabric.IPExtendedCommunityIDList{
+					IPExtendedCommunityIDs: addRouteTargets(fmt.Sprintf("%s.%sRouteTargets", field, modification.suffix), objectName("routetarget", modification.suffix), modification.routeTargets),
+				}
+			}
+		}
+		if len(communityLists) > 0 {
+			action.IPCommunityProperties = &armmanagednetworkfabric.ActionIPCommunityProperties{
+				Add:    communityLists["add"],
+				Set:    communityLists["set"],
+				Delete: communityLists["delete"],
+			}
+		}
+		if len(routeTargetLists) > 0 {
+			action.IPExtendedCommunityProperties = &armmanagednetworkfabric.ActionIPExtendedCommunityProperties{
+				Add:    routeTargetLists["add"],
+				Set:    routeTargetLists["set"],
+				Delete: routeTargetLists["delete"],
+			}
+		}
+		if actionType == armmanagednetworkfabric.RoutePolicyActionTypeDeny && (action.LocalPreference != nil || len(communityLists) > 0 || len(routeTargetLists) > 0) {
+			violations = append(violations, fmt.Sprintf("%s denies the routes it matches, so it cannot modify them", field))
+		}
+
+		statements = append(statements, &armmanagednetworkfabric.RoutePolicyStatementProperties{
+			SequenceNumber: &sequenceNumber,
+			Condition:      condition,
+			Action:         action,
+		})
+	}
+
+	// routes no statement permits or denies are denied anyway, so only a permit needs a statement
+	defaultAction, ok := routePolicyActionType(firstNonEmpty(intent.DefaultAction, string(armmanagednetworkfabric.RoutePolicyActionTypeDeny)))
+	switch {
+	case !ok || defaultAction == armmanagednetworkfabric.RoutePolicyActionTypeContinue:
+		violations = append(violations, fmt.Sprintf("defaultAction '%s' is not Permit or Deny", intent.DefaultAction))
+	case defaultAction == armmanagednetworkfabric.RoutePolicyActionTypePermit && family != "":
+		sequenceNumber := int64((len(intent.Statements) + 1) * BUILD_SEQUENCE_STEP)
+		everyRoute := "0.0.0.0/0 le 32"
+		if family == armmanagednetworkfabric.AddressFamilyTypeIPv6 {
+			everyRoute = "::/0 le 128"
+		}
+		statements = append(statements, &armmanagednetworkfabric.RoutePolicyStatementProperties{
+			SequenceNumber: &sequenceNumber,
+			Condition: &armmanagednetworkfabric.StatementConditionProperties{
+				Type:       to.Ptr(armmanagednetworkfabric.RoutePolicyConditionTypeAnd),
+				IPPrefixID: addPrefix("defaultAction", name+"-prefix-default", []string{everyRoute}),
+			},
+			Action: &armmanagednetworkfabric.StatementActionProperties{
+				ActionType: to.Ptr(armmanagednetworkfabric.RoutePolicyActionTypePermit),
+			},
+		})
+	}
+
+	if family == "" && familyOk {
+		violations = append(violations, "addressFamilyType missing, and there are no prefixes to take it from")
+	}
+
+	properties := &armmanagednetworkfabric.RoutePolicyProperties{
+		Annotation:      &annotation,
+		NetworkFabricID: &fabricId,
+		Statements:      statements,
+	}
+	if family != "" {
+		properties.AddressFamilyType = &family
+	}
+	add(ROUTE_POLICY_RESOURCE_TYPE, name, properties)
+	resources[len(resources)-1].target.FabricID = &fabricId
+
+	return resources, violations
+}
+
+// parsePrefixIntent reads a prefix of a statement intent: a CIDR, optionally followed by ge N, le N,
+// eq N or a range N-M of subnet mask lengths.
+func parsePrefixIntent(value string) (*armmanagednetworkfabric.IPPrefixRule, error) {
+	fields := strings.Fields(value)
+	rule := &armmanagednetworkfabric.IPPrefixRule{}
+
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 2 && strings.Contains(fields[1], "-"):
+		rule.Condition = to.Ptr(armmanagednetworkfabric.ConditionRange)
+		rule.SubnetMaskLength = &fields[1]
+	case len(fields) == 3 && slices.Contains([]string{"ge", "le", "eq"}, strings.ToLower(fields[1])):
+		condition := map[string]armmanagednetworkfabric.Condition{
+			"ge": armmanagednetworkfabric.ConditionGreaterThanOrEqualTo,
+			"le": armmanagednetworkfabric.ConditionLesserThanOrEqualTo,
+			"eq": armmanagednetworkfabric.ConditionEqualTo,
+		}[strings.ToLower(fields[1])]
+		rule.Condition = &condition
+		rule.SubnetMaskLength = &fields[2]
+	default:
+		return nil, fmt.Errorf("'%s' is not a prefix such as 10.2.0.0/16, optionally followed by ge N, le N, eq N or a range N-M", value)
+	}
+
+	rule.NetworkPrefix = &fields[0]
+	return rule, nil
+}
+
+// addressFamily returns the declared address family, or else the family of the first prefix of the
+// statements. ok is false when the declared family is not valid.
+func addressFamily(declared string, statements []StatementIntent) (family armmanagednetworkfabric.AddressFamilyType, ok bool) {
+	if declared != "" {
+		for _, value := range armmanagednetworkfabric.PossibleAddressFamilyTypeValues() {
+			if strings.EqualFold(string(value), declared) {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	for _, statement := range statements {
+		for _, value := range statement.Prefixes {
+			if fields := strings.Fields(value); len(fields) > 0 {
+				if prefix, err := netip.ParsePrefix(fields[0]); err == nil {
+					return addressFamilyOf(prefix), true
+				}
+			}
+		}
+	}
+	return "", true
+}
+
+func routePolicyActionType(value string) (armmanagednetworkfabric.RoutePolicyActionType, bool) {
+	for _, actionType := range armmanagednetworkfabric.PossibleRoutePolicyActionTypeValues() {
+		if strings.EqualFold(string(actionType), value) {
+			return actionType, true
+		}
+	}
+	return "", false
+}
+
+func newBuiltResource(resource buildResource) (BuiltResource, error) {
+	builtResource := BuiltResource{
+		ID:     resource.target.ID(),
+		Name:   resource.target.Name,
+		Type:   resource.target.ResourceType,
+		Status: BUILD_STATUS_PLANNED,
+	}
+
+	propertiesJson, err := json.Marshal(resource.properties)
+	if err != nil {
+		return builtResource, fmt.Errorf("failed to marshal properties: %v", err)
+	}
+	if err := json.Unmarshal(propertiesJson, &builtResource.Properties); err != nil {
+		return builtResource, fmt.Errorf("failed to unmarshal properties: %v", err)
+	}
+	return builtResource, nil
+}
+
+// buildOperations creates and deletes a resource build_routepolicy builds, waiting for the long
+// running operation to finish.
+type buildOperations struct {
+	create func(ctx context.Context, location string) error
+	delete func(ctx context.Context) error
+}
+
+// newBuildOperations returns the operations of the client for the type of the resource.
+func newBuildOperations(clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, resource buildResource) (buildOperations, error) {
+	target := resource.target
+
+	switch properties := resource.properties.(type) {
+	case *armmanagednetworkfabric.IPPrefixProperties:
+		client, err := armmanagednetworkfabric.NewIPPrefixesClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return buildOperations{}, fmt.Errorf("failed to create IP prefixes client: %v", err)
+		}
+		return buildOperations{
+			create: func(ctx context.Context, location string) error {
+				poller, err := client.BeginCreate(ctx, target.ResourceGroup, target.Name, armmanagednetworkfabric.IPPrefix{Location: &location, Properties: properties}, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+			delete: func(ctx context.Context) error {
+				poller, err := client.BeginDelete(ctx, target.ResourceGroup, target.Name, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+		}, nil
+	case *armmanagednetworkfabric.IPCommunityProperties:
+		client, err := armmanagednetworkfabric.NewIPCommunitiesClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return buildOperations{}, fmt.Errorf("failed to create IP communities client: %v", err)
+		}
+		return buildOperations{
+			create: func(ctx context.Context, location string) error {
+				poller, err := client.BeginCreate(ctx, target.ResourceGroup, target.Name, armmanagednetworkfabric.IPCommunity{Location: &location, Properties: properties}, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+			delete: func(ctx context.Context) error {
+				poller, err := client.BeginDelete(ctx, target.ResourceGroup, target.Name, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+		}, nil
+	case *armmanagednetworkfabric.IPExtendedCommunityProperties:
+		client, err := armmanagednetworkfabric.NewIPExtendedCommunitiesClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return buildOperations{}, fmt.Errorf("failed to create IP extended communities client: %v", err)
+		}
+		return buildOperations{
+			create: func(ctx context.Context, location string) error {
+				poller, err := client.BeginCreate(ctx, target.ResourceGroup, target.Name, armmanagednetworkfabric.IPExtendedCommunity{Location: &location, Properties: properties}, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+			delete: func(ctx context.Context) error {
+				poller, err := client.BeginDelete(ctx, target.ResourceGroup, target.Name, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+		}, nil
+	case *armmanagednetworkfabric.RoutePolicyProperties:
+		client, err := armmanagednetworkfabric.NewRoutePoliciesClient(target.SubscriptionID, cred, clientRetriever.ClientOptions())
+		if err != nil {
+			return buildOperations{}, fmt.Errorf("failed to create route policies client: %v", err)
+		}
+		return buildOperations{
+			create: func(ctx context.Context, location string) error {
+				poller, err := client.BeginCreate(ctx, target.ResourceGroup, target.Name, armmanagednetworkfabric.RoutePolicy{Location: &location, Properties: properties}, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+			delete: func(ctx context.Context) error {
+				poller, err := client.BeginDelete(ctx, target.ResourceGroup, target.Name, nil)
+				return pollBuildOperation(ctx, poller, err)
+			},
+		}, nil
+	}
+
+	return buildOperations{}, fmt.Errorf("unsupported resource type %s", target.ResourceType)
+}
+
+// pollBuildOperation waits for a long running operation whose begin call returned the poller and
+// error given.
+func pollBuildOperation[T any](ctx context.Context, poller *runtime.Poller[T], err error) error {
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func createBuildResource(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, location string, resource buildResource) error {
+	operations, err := newBuildOperations(clientRetriever, cred, resource)
+	if err != nil {
+		return err
+	}
+
+	timer := startOperationTimer(ctx, OPERATION_CREATE, resource.target.ResourceType)
+	err = operations.create(ctx, location)
+	timer.stop(err)
+	return err
+}
+
+func deleteBuildResource(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, resource buildResource) error {
+	operations, err := newBuildOperations(clientRetriever, cred, resource)
+	if err != nil {
+		return err
+	}
+
+	timer := startOperationTimer(ctx, OPERATION_DELETE, resource.target.ResourceType)
+	err = operations.delete(ctx)
+	timer.stop(err)
+	return err
+}
+
+// rollbackRoutePolicyBuild deletes the resources of a build whose last step failed, newest first,
+// and reports both the failure and the rollback. The resource of the failed step is deleted as well
+// when ARM left it behind, e.g. in the Failed state or because the request was cancelled while
+// polling. It carries on when the request is cancelled, so that a cancelled build does not leave
+// resources behind.
+func rollbackRoutePolicyBuild(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, resources []buildResource, err error) *mcp.CallToolResult {
+	failed := resources[len(resources)-1]
+	message := fmt.Sprintf("Failed to create %s '%s'", failed.target.ResourceType, failed.target.Name)
+	if armErr, ok := decodeARMError(err); ok {
+		message += ".\n" + armErr.String()
+	} else {
+		message += fmt.Sprintf(": %v", err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	rollback := []string{}
+	for i, resource := range slices.Backward(resources) {
+		if i == len(resources)-1 {
+			exists, err := buildResourceExists(ctx, clientRetriever, cred, resource)
+			if err != nil {
+				rollback = append(rollback, fmt.Sprintf("failed to check whether %s '%s' was left behind, delete it manually if it exists: %v", resource.target.ResourceType, resource.target.Name, err))
+				continue
+			}
+			if !exists {
+				continue
+			}
+		}
+
+		if err := deleteBuildResource(ctx, clientRetriever, cred, resource); err != nil {
+			// a resource that is gone already needs no rollback
+			if armErr, ok := decodeARMError(err); !ok || armErr.StatusCode != http.StatusNotFound {
+				rollback = append(rollback, fmt.Sprintf("failed to delete %s '%s', delete it manually: %v", resource.target.ResourceType, resource.target.Name, err))
+				continue
+			}
+		}
+		rollback = append(rollback, fmt.Sprintf("deleted %s '%s'", resource.target.ResourceType, resource.target.Name))
+	}
+
+	if len(rollback) == 0 {
+		return mcp.NewToolResultError(message + "\n\nNothing had been created, so nothing was rolled back.")
+	}
+	return mcp.NewToolResultError(message + "\n\nRolled back the resources of the build:\n- " + strings.Join(rollback, "\n- "))
+}
+
+// buildResourceExists tells whether a resource of the build exists, in whatever state.
+func buildResourceExists(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, resource buildResource) (bool, error) {
+	client, err := armresources.NewClient(resource.target.SubscriptionID, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return false, fmt.Errorf("failed to create resources client: %v", err)
+	}
+	_, exists, err := getGenericResource(ctx, client, resource.target.ID())
+	return exists, err
+}
+
+// String renders the build for the text result.
+func (build RoutePolicyBuild) String() string {
+	result := build.Message + "\n"
+	for _, resource := range build.Resources {
+		result += fmt.Sprintf("- %s %s: %s\n", resource.Status, resource.Type, resource.ID)
+	}
+	for _, warning := range build.Warnings {
+		result += fmt.Sprintf("Warning: %s\n", warning)
+	}
+	return result
+}
@@ -0,0 +1,371 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testOptional dereferences an optional SDK string, returning "" when it is not set.
+func testOptional[T ~string](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return string(*value)
+}
+
+func TestParsePrefixIntent(t *testing.T) {
+	tests := []struct {
+		value     string
+		prefix    string
+		condition armmanagednetworkfabric.Condition
+		length    string
+		err       bool
+	}{
+		{value: "10.2.0.0/16", prefix: "10.2.0.0/16"},
+		{value: " 10.2.0.0/16 ge 24", prefix: "10.2.0.0/16", condition: armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, length: "24"},
+		{value: "10.2.0.0/16 LE 20", prefix: "10.2.0.0/16", condition: armmanagednetworkfabric.ConditionLesserThanOrEqualTo, length: "20"},
+		{value: "10.2.0.0/16 eq 24", prefix: "10.2.0.0/16", condition: armmanagednetworkfabric.ConditionEqualTo, length: "24"},
+		{value: "10.2.0.0/16 24-28", prefix: "10.2.0.0/16", condition: armmanagednetworkfabric.ConditionRange, length: "24-28"},
+		{value: "fd00::/48 ge 64", prefix: "fd00::/48", condition: armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, length: "64"},
+		{value: "10.2.0.0/16 gt 24", err: true},
+		{value: "10.2.0.0/16 24", err: true},
+		{value: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			rule, err := parsePrefixIntent(test.value)
+			if test.err {
+				if err == nil {
+					t.Fatalf("parsePrefixIntent = %v, want an error", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePrefixIntent failed: %v", err)
+			}
+			if *rule.NetworkPrefix != test.prefix {
+				t.Errorf("prefix = %s, want %s", *rule.NetworkPrefix, test.prefix)
+			}
+			if condition := testOptional(rule.Condition); condition != string(test.condition) {
+				t.Errorf("condition = %s, want %s", condition, test.condition)
+			}
+			if length := testOptional(rule.SubnetMaskLength); length != test.length {
+				t.Errorf("subnet mask length = %s, want %s", length, test.length)
+			}
+		})
+	}
+}
+
+func TestPlanRoutePolicyBuild(t *testing.T) {
+	tests := []struct {
+		name       string
+		intent     RoutePolicyIntent
+		resources  []string
+		statements []int64
+		family     armmanagednetworkfabric.AddressFamilyType
+		violations []string
+	}{
+		{
+			name: "objects named after the statements",
+			intent: RoutePolicyIntent{Statements: []StatementIntent{
+				{Action: "Continue", Prefixes: []string{"10.2.0.0/16 ge 24"}, AddCommunities: []string{"65001:100"}},
+				{Action: "Permit", Communities: []string{"65001:100", "NoExport"}, RouteTargets: []string{"65001:1"}, DeleteCommunities: []string{"NoExport"}},
+				{Action: "Deny", Prefixes: []string{"10.3.0.0/16"}},
+			}},
+			resources: []string{
+				"rp1-prefix-10", "rp1-community-10-add",
+				"rp1-community-20", "rp1-routetarget-20", "rp1-community-20-delete",
+				"rp1-prefix-30",
+				"rp1",
+			},
+			statements: []int64{10, 20, 30},
+			family:     armmanagednetworkfabric.AddressFamilyTypeIPv4,
+		},
+		{
+			name: "default permit",
+			intent: RoutePolicyIntent{DefaultAction: "Permit", Statements: []StatementIntent{
+				{Action: "Deny", Prefixes: []string{"fd00::/48 le 64"}},
+			}},
+			resources:  []string{"rp1-prefix-10", "rp1-prefix-default", "rp1"},
+			statements: []int64{10, 20},
+			family:     armmanagednetworkfabric.AddressFamilyTypeIPv6,
+		},
+		{
+			name: "declared family",
+			intent: RoutePolicyIntent{AddressFamilyType: "ipv6", Statements: []StatementIntent{
+				{Action: "Permit", Communities: []string{"65001:100"}},
+			}},
+			resources:  []string{"rp1-community-10", "rp1"},
+			statements: []int64{10},
+			family:     armmanagednetworkfabric.AddressFamilyTypeIPv6,
+		},
+		{
+			name:       "no statements",
+			intent:     RoutePolicyIntent{AddressFamilyType: "IPv4"},
+			resources:  []string{"rp1"},
+			statements: []int64{},
+			family:     armmanagednetworkfabric.AddressFamilyTypeIPv4,
+			violations: []string{"statements: at least one statement is needed"},
+		},
+		{
+			name: "invalid statements",
+			intent: RoutePolicyIntent{DefaultAction: "Continue", Statements: []StatementIntent{
+				{Action: "Drop", Prefixes: []string{"10.2.0.0/16 gt 24"}},
+				{Action: "Deny", Prefixes: []string{"10.3.0.0/16"}, LocalPreference: new(int64)},
+				{Action: "Permit"},
+			}},
+			resources:  []string{"rp1-prefix-10", "rp1-prefix-20", "rp1"},
+			statements: []int64{10, 20, 30},
+			family:     armmanagednetworkfabric.AddressFamilyTypeIPv4,
+			violations: []string{
+				"statements[0].action 'Drop' is not one of",
+				"statements[0].prefixes[0]: '10.2.0.0/16 gt 24' is not a prefix",
+				"statements[1] denies the routes it matches, so it cannot modify them",
+				"statements[2] matches nothing",
+				"defaultAction 'Continue' is not Permit or Deny",
+			},
+		},
+		{
+			name: "no address family",
+			intent: RoutePolicyIntent{Statements: []StatementIntent{
+				{Action: "Permit", Communities: []string{"65001:100"}},
+			}},
+			resources:  []string{"rp1-community-10", "rp1"},
+			statements: []int64{10},
+			violations: []string{"addressFamilyType missing"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resources, violations := planRoutePolicyBuild(testSubscriptionID, "rg1", "rp1", "/fabric", test.intent)
+
+			names := []string{}
+			ids := map[string]bool{}
+			for _, resource := range resources {
+				names = append(names, resource.target.Name)
+				ids[resource.target.ID()] = true
+			}
+			if !slices.Equal(names, test.resources) {
+				t.Errorf("resources = %v, want %v", names, test.resources)
+			}
+
+			for _, want := range test.violations {
+				if !slices.ContainsFunc(violations, func(violation string) bool { return strings.HasPrefix(violation, want) }) {
+					t.Errorf("violation %q missing from %v", want, violations)
+				}
+			}
+			if len(violations) != len(test.violations) {
+				t.Errorf("violations = %v, want %d", violations, len(test.violations))
+			}
+
+			policy := resources[len(resources)-1]
+			if policy.target.ResourceType != ROUTE_POLICY_RESOURCE_TYPE || stringValue(policy.target.FabricID) != "/fabric" {
+				t.Fatalf("last resource = %s in fabric %s, want the route policy", policy.target.ResourceType, stringValue(policy.target.FabricID))
+			}
+			properties := policy.properties.(*armmanagednetworkfabric.RoutePolicyProperties)
+			if family := testOptional(properties.AddressFamilyType); family != string(test.family) {
+				t.Errorf("address family = %s, want %s", family, test.family)
+			}
+
+			sequenceNumbers := []int64{}
+			for _, statement := range properties.Statements {
+				sequenceNumbers = append(sequenceNumbers, *statement.SequenceNumber)
+				// every object a statement references is created before the route policy
+				references := []*string{statement.Condition.IPPrefixID}
+				references = append(references, statement.Condition.IPCommunityIDs...)
+				references = append(references, statement.Condition.IPExtendedCommunityIDs...)
+				if communities := statement.Action.IPCommunityProperties; communities != nil {
+					for _, list := range []*armmanagednetworkfabric.IPCommunityIDList{communities.Add, communities.Set, communities.Delete} {
+						if list != nil {
+							references = append(references, list.IPCommunityIDs...)
+						}
+					}
+				}
+				for _, reference := range references {
+					if reference != nil && !ids[*reference] {
+						t.Errorf("statement %d references %s, which is not built", *statement.SequenceNumber, *reference)
+					}
+				}
+			}
+			if !slices.Equal(sequenceNumbers, test.statements) {
+				t.Errorf("statements = %v, want %v", sequenceNumbers, test.statements)
+			}
+		})
+	}
+}
+
+// fakeBuildARM serves the ARM calls of build_routepolicy from memory, keyed by resource name. A PUT
+// of a resource in failed is left behind in the Failed state, and one in rejected is refused.
+type fakeBuildARM struct {
+	existing map[string]bool
+	failed   string
+	rejected string
+}
+
+func (f *fakeBuildARM) Do(req *policy.Request) (*http.Response, error) {
+	r := req.Raw()
+	name := strings.ToLower(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	response := func(statusCode int, body string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	}
+	resource := func(state string) string {
+		return `{"id":"` + r.URL.Path + `","name":"` + name + `","properties":{"provisioningState":"` + state + `"}}`
+	}
+	notFound := `{"error":{"code":"ResourceNotFound","message":"not found"}}`
+
+	switch r.Method {
+	case http.MethodGet:
+		if f.existing[name] || strings.Contains(strings.ToLower(r.URL.Path), "/networkfabrics/") {
+			return response(http.StatusOK, resource("Succeeded"))
+		}
+		return response(http.StatusNotFound, notFound)
+	case http.MethodPut:
+		switch name {
+		case f.rejected:
+			return response(http.StatusBadRequest, `{"error":{"code":"BadRequest","message":"rejected"}}`)
+		case f.failed:
+			f.existing[name] = true
+			return response(http.StatusCreated, resource("Failed"))
+		}
+		f.existing[name] = true
+		return response(http.StatusOK, resource("Succeeded"))
+	case http.MethodDelete:
+		if !f.existing[name] {
+			return response(http.StatusNotFound, notFound)
+		}
+		delete(f.existing, name)
+		return response(http.StatusNoContent, "")
+	}
+	return response(http.StatusMethodNotAllowed, "")
+}
+
+// testBuildRequest builds rp1 from a statement that needs an IP prefix and an IP community.
+func testBuildRequest() mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"subscriptionId":    testSubscriptionID,
+		"resourceGroupName": "rg1",
+		"name":              "rp1",
+		"location":          "eastus",
+		"fabricName":        "fabric1",
+		"intent": map[string]any{"statements": []any{
+			map[string]any{"action": "Permit", "prefixes": []any{"10.2.0.0/16"}, "addCommunities": []any{"65001:100"}},
+		}},
+	}
+	return request
+}
+
+func TestBuildRoutePolicyConfirmation(t *testing.T) {
+	arm := &fakeBuildARM{existing: map[string]bool{}}
+	_, handler := BuildRoutePolicy(ServiceClientRetriever{
+		Credential:       ReplayCredential{},
+		PerRetryPolicies: []policy.Policy{arm},
+	})
+
+	// there is no MCP session to ask, so the build is aborted before anything is created
+	result, err := handler(context.Background(), testBuildRequest())
+	if err != nil {
+		t.Fatalf("build_routepolicy failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("build_routepolicy succeeded without confirmation")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Build Route Policy 'rp1' in resource group 'rg1' was aborted") {
+		t.Errorf("result = %s, want the build aborted", text)
+	}
+	if len(arm.existing) > 0 {
+		t.Errorf("created %v without confirmation", arm.existing)
+	}
+}
+
+func TestRoutePolicyBuildImpact(t *testing.T) {
+	resources, violations := planRoutePolicyBuild(testSubscriptionID, "rg1", "rp1", "fabric1", RoutePolicyIntent{
+		Statements: []StatementIntent{{Action: "Permit", Prefixes: []string{"10.2.0.0/16"}, AddCommunities: []string{"65001:100"}}},
+	})
+	if len(violations) > 0 {
+		t.Fatalf("planRoutePolicyBuild found violations: %v", violations)
+	}
+
+	impact := routePolicyBuildImpact(resources)
+	want := "3 resources will be created, the route policy last:\n" +
+		"- rp1-prefix-10 (" + IP_PREFIX_RESOURCE_TYPE + ")\n" +
+		"- rp1-community-10-add (" + IP_COMMUNITY_RESOURCE_TYPE + ")\n" +
+		"- rp1 (" + ROUTE_POLICY_RESOURCE_TYPE + ")\n"
+	if impact != want {
+		t.Errorf("impact =\n%s\nwant\n%s", impact, want)
+	}
+}
+
+func TestBuildRoutePolicyRollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		failed   string
+		rejected string
+		want     []string
+	}{
+		{
+			name:   "failed step left behind",
+			failed: "rp1-community-10-add",
+			want:   []string{"Failed to create", "deleted " + IP_COMMUNITY_RESOURCE_TYPE + " 'rp1-community-10-add'", "deleted " + IP_PREFIX_RESOURCE_TYPE + " 'rp1-prefix-10'"},
+		},
+		{
+			name:     "failed step rejected",
+			rejected: "rp1-community-10-add",
+			want:     []string{"rejected", "Rolled back the resources of the build:\n- deleted " + IP_PREFIX_RESOURCE_TYPE + " 'rp1-prefix-10'"},
+		},
+		{
+			name:     "first step rejected",
+			rejected: "rp1-prefix-10",
+			want:     []string{"Nothing had been created, so nothing was rolled back."},
+		},
+		{
+			name:   "first step left behind",
+			failed: "rp1-prefix-10",
+			want:   []string{"Rolled back the resources of the build:\n- deleted " + IP_PREFIX_RESOURCE_TYPE + " 'rp1-prefix-10'"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			arm := &fakeBuildARM{existing: map[string]bool{}, failed: test.failed, rejected: test.rejected}
+			_, handler := BuildRoutePolicy(ServiceClientRetriever{
+				Credential:       ReplayCredential{},
+				PerRetryPolicies: []policy.Policy{arm},
+				SkipConfirmation: true,
+			})
+
+			result, err := handler(context.Background(), testBuildRequest())
+			if err != nil {
+				t.Fatalf("build_routepolicy failed: %v", err)
+			}
+			if !result.IsError {
+				t.Fatalf("build_routepolicy succeeded, want a failure")
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			for _, want := range test.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("result misses %q:\n%s", want, text)
+				}
+			}
+			if len(arm.existing) > 0 {
+				t.Errorf("left behind %v", arm.existing)
+			}
+		})
+	}
+}
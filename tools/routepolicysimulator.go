@@ -0,0 +1,553 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/netip"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// wellKnownCommunityValues are the community values of the well-known communities. Internet is left
+// out since every route is part of it.
+var wellKnownCommunityValues = map[armmanagednetworkfabric.WellKnownCommunities]string{
+	armmanagednetworkfabric.WellKnownCommunitiesNoExport:    "65535:65281",
+	armmanagednetworkfabric.WellKnownCommunitiesNoAdvertise: "65535:65282",
+	armmanagednetworkfabric.WellKnownCommunitiesLocalAS:     "65535:65283",
+	armmanagednetworkfabric.WellKnownCommunitiesGShut:       "65535:0",
+}
+
+// listRule is a rule of an IP community or IP extended community. It matches routes that carry all
+// of its values. Internet adds no value, as every route carries it, so a rule of only Internet
+// matches every route.
+type listRule struct {
+	sequenceNumber int64
+	action         armmanagednetworkfabric.CommunityActionTypes
+	values         []string
+	internet       bool
+}
+
+func SimulateRoutePolicy(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return simulateRoutePolicy(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		name, ok := args["name"].(string)
+		if !ok || name == "" {
+			return nil, errors.New("Route Policy name missing")
+		}
+
+		resourceGroupName, ok := args["resourceGroupName"].(string)
+		if !ok || resourceGroupName == "" {
+			return nil, errors.New("resource group name missing")
+		}
+
+		subscriptionId, ok := args["subscriptionId"].(string)
+		if !ok || subscriptionId == "" {
+			return nil, errors.New("subscription id missing")
+		}
+
+		route, err := parseRoute(args)
+		if err != nil {
+			return nil, err
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		model, err := loadRoutePolicy(ctx, clientRetriever, cred, subscriptionId, resourceGroupName, name)
+		if err != nil {
+			return armErrorResult("failed to get route policy", err)
+		}
+
+		simulation := model.simulate(route)
+		return mcp.NewToolResultStructured(simulation, simulation.String()), nil
+	}
+}
+
+func simulateRoutePolicy() mcp.Tool {
+	return mcp.NewTool(
+		SIMULATE_ROUTE_POLICY_TOOL_NAME,
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description(ROUTE_POLICY_PARAMETER_DESCRIPTION),
+		),
+		mcp.WithString("prefix",
+			mcp.Required(),
+			mcp.Description(ROUTE_PREFIX_DESCRIPTION),
+		),
+		mcp.WithArray("communities",
+			mcp.Description(ROUTE_COMMUNITIES_DESCRIPTION),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("extendedCommunities",
+			mcp.Description(ROUTE_EXT_COMMUNITIES_DESCRIPTION),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("localPreference",
+			mcp.Description(ROUTE_LOCAL_PREFERENCE_DESCRIPTION),
+		),
+		mcp.WithString("resourceGroupName",
+			mcp.Description(ROUTE_POLICY_RESOURCE_GROUP_DESCRIPTION),
+		),
+		mcp.WithString("subscriptionId",
+			mcp.Description(ROUTE_POLICY_SUBSCRIPTION_ID_DESCRIPTION),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[RouteSimulation](),
+		mcp.WithDescription("Evaluate a Route Policy locally against a hypothetical route, using the IP prefixes, IP communities and IP extended communities its statements reference. Reports the statement that matches, whether the route is permitted or denied and how its communities and local preference are modified. Nothing is changed in Azure."),
+	)
+}
+
+// parseRoute reads the route to simulate, writing its communities the way the policy is compared
+// against them.
+func parseRoute(args map[string]any) (Route, error) {
+	prefix, ok := args["prefix"].(string)
+	if !ok || prefix == "" {
+		return Route{}, errors.New("route prefix missing")
+	}
+	if _, err := netip.ParsePrefix(prefix); err != nil {
+		return Route{}, fmt.Errorf("invalid route prefix '%s': %v", prefix, err)
+	}
+
+	route := Route{Prefix: prefix, Communities: []string{}, ExtendedCommunities: []string{}}
+
+	communities, _ := args["communities"].([]any)
+	for _, value := range communities {
+		community, _ := value.(string)
+		normalized, ok := normalizeCommunity(community)
+		if !ok {
+			return Route{}, fmt.Errorf("invalid community '%v', expected ASN:NN, a 32-bit number or a well-known community such as no-export", value)
+		}
+		if normalized != "" {
+			route.Communities = appendUnique(route.Communities, normalized)
+		}
+	}
+
+	routeTargets, _ := args["extendedCommunities"].([]any)
+	for _, value := range routeTargets {
+		routeTarget, _ := value.(string)
+		normalized := normalizeRouteTarget(routeTarget)
+		if !validRouteTarget(normalized) {
+			return Route{}, fmt.Errorf("invalid route target '%v', expected ASN:NN, ASN.ASN:NN or IP:NN", value)
+		}
+		route.ExtendedCommunities = appendUnique(route.ExtendedCommunities, normalized)
+	}
+
+	if value, ok := args["localPreference"]; ok && value != nil {
+		localPreference, ok := value.(float64)
+		if !ok || localPreference != math.Trunc(localPreference) || localPreference < 0 || localPreference > math.MaxUint32 {
+			return Route{}, fmt.Errorf("invalid local preference '%v', expected a whole number from 0 to %d", value, uint32(math.MaxUint32))
+		}
+		preference := int64(localPreference)
+		route.LocalPreference = &preference
+	}
+
+	return route, nil
+}
+
+// simulate evaluates the statements by ascending sequence number until one permits or denies the
+// route. Continue statements apply their modifications and pass the modified route on to the next
+// statement.
+func (model *routePolicyModel) simulate(route Route) RouteSimulation {
+	simulation := RouteSimulation{
+		RoutePolicyID: stringValue(model.policy.ID),
+		Route:         route,
+		Action:        string(armmanagednetworkfabric.RoutePolicyActionTypeDeny),
+		Modifications: []string{},
+		Trace:         []StatementEvaluation{},
+		Warnings:      []string{},
+	}
+	warn := func(format string, a ...any) {
+		simulation.Warnings = append(simulation.Warnings, fmt.Sprintf(format, a...))
+	}
+
+	prefix := netip.MustParsePrefix(route.Prefix)
+	if prefix != prefix.Masked() {
+		warn("the route prefix %s has host bits set, it is evaluated as %s", prefix, prefix.Masked())
+		prefix = prefix.Masked()
+	}
+	if model.policy.Properties != nil && model.policy.Properties.AddressFamilyType != nil && addressFamilyOf(prefix) != *model.policy.Properties.AddressFamilyType {
+		warn("the route is %s, but the route policy is %s", addressFamilyOf(prefix), *model.policy.Properties.AddressFamilyType)
+	}
+	references := model.references()
+	for _, id := range slices.Sorted(maps.Keys(model.referenceProblems)) {
+		warn("%s '%s' %s, so it matches nothing", referenceKinds[references[id]], path.Base(id), model.referenceProblems[id])
+	}
+
+	current := Route{
+		Prefix:              prefix.String(),
+		Communities:         slices.Clone(route.Communities),
+		ExtendedCommunities: slices.Clone(route.ExtendedCommunities),
+		LocalPreference:     route.LocalPreference,
+	}
+
+	ordered := model.orderedStatements()
+	for i, statement := range ordered {
+		sequenceNumber := *statement.SequenceNumber
+		if i+1 < len(ordered) && *ordered[i+1].SequenceNumber == sequenceNumber {
+			// the sort is stable, so the statement listed last comes last
+			if i == 0 || *ordered[i-1].SequenceNumber != sequenceNumber {
+				warn(DUPLICATE_SEQUENCE_NUMBER_WARNING, sequenceNumber)
+			}
+			continue
+		}
+
+		matched, reason := model.conditionMatches(statement.Condition, prefix, current)
+		evaluation := StatementEvaluation{SequenceNumber: sequenceNumber, Matched: matched, Reason: reason}
+		if !matched {
+			simulation.Trace = append(simulation.Trace, evaluation)
+			continue
+		}
+		if statement.Action == nil || statement.Action.ActionType == nil {
+			evaluation.Reason += ", but it has no action type"
+			simulation.Trace = append(simulation.Trace, evaluation)
+			continue
+		}
+		evaluation.Action = string(*statement.Action.ActionType)
+		simulation.Trace = append(simulation.Trace, evaluation)
+
+		if *statement.Action.ActionType == armmanagednetworkfabric.RoutePolicyActionTypeDeny {
+			simulation.MatchedStatement = statement.SequenceNumber
+			return simulation
+		}
+
+		model.applyActions(statement, &current, &simulation)
+		if *statement.Action.ActionType == armmanagednetworkfabric.RoutePolicyActionTypePermit {
+			simulation.Action = string(armmanagednetworkfabric.RoutePolicyActionTypePermit)
+			simulation.MatchedStatement = statement.SequenceNumber
+			simulation.Result = &current
+			return simulation
+		}
+	}
+
+	return simulation
+}
+
+// String renders the simulation for the text result.
+func (simulation RouteSimulation) String() string {
+	result := fmt.Sprintf("Route %s through route policy %s:\n", simulation.Route.Prefix, simulation.RoutePolicyID)
+	for _, evaluation := range simulation.Trace {
+		outcome := "no match"
+		if evaluation.Matched {
+			outcome = strings.ToLower(evaluation.Action)
+		}
+		result += fmt.Sprintf("- %d: %s, %s\n", evaluation.SequenceNumber, outcome, evaluation.Reason)
+	}
+
+	if simulation.MatchedStatement == nil {
+		result += fmt.Sprintf("\n%s by the implicit deny at the end of the policy.\n", simulation.Action)
+	} else {
+		result += fmt.Sprintf("\n%s by statement %d.\n", simulation.Action, *simulation.MatchedStatement)
+	}
+
+	if len(simulation.Modifications) > 0 {
+		result += "\nModifications:\n- " + strings.Join(simulation.Modifications, "\n- ") + "\n"
+	}
+	if simulation.Result != nil {
+		result += fmt.Sprintf("\nResulting route: communities [%s], extended communities [%s]", strings.Join(simulation.Result.Communities, ", "), strings.Join(simulation.Result.ExtendedCommunities, ", "))
+		if simulation.Result.LocalPreference != nil {
+			result += fmt.Sprintf(", local preference %d", *simulation.Result.LocalPreference)
+		}
+		result += "\n"
+	}
+	if len(simulation.Warnings) > 0 {
+		result += "\nWarnings:\n- " + strings.Join(simulation.Warnings, "\n- ") + "\n"
+	}
+	return result
+}
+
+func (model *routePolicyModel) conditionMatches(condition *armmanagednetworkfabric.StatementConditionProperties, prefix netip.Prefix, route Route) (bool, string) {
+	criteria := conditionCriteria(condition)
+	if len(criteria) == 0 {
+		return true, "the statement matches every route"
+	}
+
+	reasons := make([]string, 0, len(criteria))
+	matches := 0
+	for _, criterion := range criteria {
+		matched, reason := model.criterionMatches(criterion, prefix, route)
+		if matched {
+			matches++
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if isOr(condition) {
+		return matches > 0, strings.Join(reasons, "; ")
+	}
+	return matches == len(criteria), strings.Join(reasons, "; ")
+}
+
+// criterionMatches evaluates one criterion of a condition. A community criterion matches when any
+// of its IP communities does.
+func (model *routePolicyModel) criterionMatches(criterion matchCriterion, prefix netip.Prefix, route Route) (bool, string) {
+	if criterion.resourceType == IP_PREFIX_RESOURCE_TYPE {
+		return model.prefixListMatches(criterion.ids[0], prefix)
+	}
+
+	reasons := make([]string, 0, len(criterion.ids))
+	for _, id := range criterion.ids {
+		var matched bool
+		var reason string
+		if criterion.resourceType == IP_COMMUNITY_RESOURCE_TYPE {
+			rules, ok := model.communityRules(id)
+			matched, reason = listMatches(id, criterion.resourceType, rules, ok, route.Communities, "the communities of the route")
+		} else {
+			rules, ok := model.extCommunityRules(id)
+			matched, reason = listMatches(id, criterion.resourceType, rules, ok, route.ExtendedCommunities, "the route targets of the route")
+		}
+		if matched {
+			return true, reason
+		}
+		reasons = append(reasons, reason)
+	}
+	return false, strings.Join(reasons, "; ")
+}
+
+// prefixListMatches evaluates the rules of an IP prefix by ascending sequence number. The first rule
+// that matches the route permits or denies it.
+func (model *routePolicyModel) prefixListMatches(id string, prefix netip.Prefix) (bool, string) {
+	properties, ok := model.prefixes[id]
+	if !ok {
+		return false, fmt.Sprintf("IP prefix '%s' could not be evaluated", path.Base(id))
+	}
+
+	rules := slices.Clone(properties.IPPrefixRules)
+	slices.SortStableFunc(rules, func(a, b *armmanagednetworkfabric.IPPrefixRule) int {
+		return int(int64Value(a.SequenceNumber) - int64Value(b.SequenceNumber))
+	})
+
+	route := prefixMatch{prefix: prefix, low: prefix.Bits(), high: prefix.Bits()}
+	for _, rule := range rules {
+		if rule == nil || rule.Action == nil {
+			continue
+		}
+		match, ok := newPrefixMatch(rule)
+		if !ok || !route.within(match) {
+			continue
+		}
+		if *rule.Action == armmanagednetworkfabric.CommunityActionTypesPermit {
+			return true, fmt.Sprintf("IP prefix '%s' rule %d permits %s", path.Base(id), int64Value(rule.SequenceNumber), prefix)
+		}
+		return false, fmt.Sprintf("IP prefix '%s' rule %d denies %s", path.Base(id), int64Value(rule.SequenceNumber), prefix)
+	}
+	return false, fmt.Sprintf("no rule of IP prefix '%s' matches %s", path.Base(id), prefix)
+}
+
+// listMatches evaluates the rules of an IP community or IP extended community by ascending sequence
+// number against the values the route carries.
+func listMatches(id, resourceType string, rules []listRule, ok bool, carried []string, subject string) (bool, string) {
+	kind, name := referenceKinds[resourceType], path.Base(id)
+	if !ok {
+		return false, fmt.Sprintf("%s '%s' could not be evaluated", kind, name)
+	}
+
+	for _, rule := range rules {
+		if len(rule.values) == 0 && !rule.internet {
+			continue
+		}
+		if !slices.ContainsFunc(rule.values, func(value string) bool { return !slices.Contains(carried, value) }) {
+			if rule.action == armmanagednetworkfabric.CommunityActionTypesPermit {
+				return true, fmt.Sprintf("%s '%s' rule %d permits %s", kind, name, rule.sequenceNumber, subject)
+			}
+			return false, fmt.Sprintf("%s '%s' rule %d denies %s", kind, name, rule.sequenceNumber, subject)
+		}
+	}
+	return false, fmt.Sprintf("no rule of %s '%s' matches %s", kind, name, subject)
+}
+
+// communityRules returns the rules of an IP community in evaluation order, with their members
+// written the way route communities are.
+func (model *routePolicyModel) communityRules(id string) ([]listRule, bool) {
+	properties, ok := model.communities[id]
+	if !ok {
+		return nil, false
+	}
+
+	rules := []listRule{}
+	for _, rule := range properties.IPCommunityRules {
+		if rule == nil || rule.Action == nil {
+			continue
+		}
+		result := listRule{sequenceNumber: int64Value(rule.SequenceNumber), action: *rule.Action}
+		for _, member := range rule.CommunityMembers {
+			if member == nil {
+				continue
+			}
+			if value, ok := normalizeCommunity(*member); ok && value != "" {
+				result.values = appendUnique(result.values, value)
+			} else if ok {
+				result.internet = true
+			}
+		}
+		for _, wellKnown := range rule.WellKnownCommunities {
+			if wellKnown == nil {
+				continue
+			}
+			if value, ok := wellKnownCommunityValues[*wellKnown]; ok {
+				result.values = appendUnique(result.values, value)
+			} else if *wellKnown == armmanagednetworkfabric.WellKnownCommunitiesInternet {
+				result.internet = true
+			}
+		}
+		rules = append(rules, result)
+	}
+	slices.SortStableFunc(rules, func(a, b listRule) int { return int(a.sequenceNumber - b.sequenceNumber) })
+	return rules, true
+}
+
+// extCommunityRules returns the rules of an IP extended community in evaluation order.
+func (model *routePolicyModel) extCommunityRules(id string) ([]listRule, bool) {
+	properties, ok := model.extCommunities[id]
+	if !ok {
+		return nil, false
+	}
+
+	rules := []listRule{}
+	for _, rule := range properties.IPExtendedCommunityRules {
+		if rule == nil || rule.Action == nil {
+			continue
+		}
+		result := listRule{sequenceNumber: int64Value(rule.SequenceNumber), action: *rule.Action}
+		for _, routeTarget := range rule.RouteTargets {
+			if routeTarget != nil {
+				result.values = appendUnique(result.values, normalizeRouteTarget(*routeTarget))
+			}
+		}
+		rules = append(rules, result)
+	}
+	slices.SortStableFunc(rules, func(a, b listRule) int { return int(a.sequenceNumber - b.sequenceNumber) })
+	return rules, true
+}
+
+// applyActions applies the local preference and community modifications of a matching statement.
+// Communities are set first, then deleted, then added.
+func (model *routePolicyModel) applyActions(statement *armmanagednetworkfabric.RoutePolicyStatementProperties, route *Route, simulation *RouteSimulation) {
+	action := statement.Action
+	sequenceNumber := *statement.SequenceNumber
+	modify := func(format string, a ...any) {
+		simulation.Modifications = append(simulation.Modifications, fmt.Sprintf("statement %d ", sequenceNumber)+fmt.Sprintf(format, a...))
+	}
+
+	if action.LocalPreference != nil {
+		route.LocalPreference = action.LocalPreference
+		modify("sets local preference to %d", *action.LocalPreference)
+	}
+
+	if communities := action.IPCommunityProperties; communities != nil {
+		values := func(list *armmanagednetworkfabric.IPCommunityIDList) []string {
+			if list == nil {
+				return nil
+			}
+			return model.actionValues(list.IPCommunityIDs, model.communityRules)
+		}
+		route.Communities = modifyValues(route.Communities, values(communities.Set), values(communities.Delete), values(communities.Add), "communities", modify)
+	}
+
+	if extCommunities := action.IPExtendedCommunityProperties; extCommunities != nil {
+		values := func(list *armmanagednetworkfabric.IPExtendedCommunityIDList) []string {
+			if list == nil {
+				return nil
+			}
+			return model.actionValues(list.IPExtendedCommunityIDs, model.extCommunityRules)
+		}
+		route.ExtendedCommunities = modifyValues(route.ExtendedCommunities, values(extCommunities.Set), values(extCommunities.Delete), values(extCommunities.Add), "route targets", modify)
+	}
+}
+
+// actionValues returns the values of the permit rules of the IP communities or IP extended
+// communities referenced by an action. Objects that could not be fetched contribute nothing.
+func (model *routePolicyModel) actionValues(ids []*string, rules func(id string) ([]listRule, bool)) []string {
+	values := []string{}
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+		listRules, _ := rules(strings.ToLower(*id))
+		for _, rule := range listRules {
+			if rule.action == armmanagednetworkfabric.CommunityActionTypesPermit {
+				for _, value := range rule.values {
+					values = appendUnique(values, value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func modifyValues(current, set, remove, add []string, subject string, modify func(format string, a ...any)) []string {
+	if set != nil {
+		current = slices.Clone(set)
+		modify("sets %s to [%s]", subject, strings.Join(set, ", "))
+	}
+	if remove != nil {
+		current = slices.DeleteFunc(current, func(value string) bool { return slices.Contains(remove, value) })
+		modify("deletes %s [%s]", subject, strings.Join(remove, ", "))
+	}
+	for _, value := range add {
+		current = appendUnique(current, value)
+	}
+	if add != nil {
+		modify("adds %s [%s]", subject, strings.Join(add, ", "))
+	}
+	return current
+}
+
+// normalizeCommunity writes a standard community as ASN:NN. Plain 32-bit numbers are split into
+// their high and low 16 bits, and well-known communities are written as their values. Internet is
+// written as the empty string, as it is no value a route carries.
+func normalizeCommunity(community string) (string, bool) {
+	community = strings.TrimSpace(community)
+	if wellKnown, ok := wellKnownCommunity(community); ok {
+		return wellKnownCommunityValues[wellKnown], true
+	}
+	if !validCommunity(community) {
+		return "", false
+	}
+
+	asn, value, ok := strings.Cut(community, ":")
+	if !ok {
+		number, _ := strconv.ParseUint(community, 10, 32)
+		return fmt.Sprintf("%d:%d", number>>16, number&0xffff), true
+	}
+	high, _ := strconv.ParseUint(asn, 10, 16)
+	low, _ := strconv.ParseUint(value, 10, 16)
+	return fmt.Sprintf("%d:%d", high, low), true
+}
+
+// normalizeRouteTarget strips the rt: or target: prefix some devices print route targets with.
+func normalizeRouteTarget(routeTarget string) string {
+	routeTarget = strings.ToLower(strings.TrimSpace(routeTarget))
+	for _, prefix := range []string{"rt:", "rt ", "target:"} {
+		routeTarget = strings.TrimPrefix(routeTarget, prefix)
+	}
+	return strings.TrimSpace(routeTarget)
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func int64Value(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
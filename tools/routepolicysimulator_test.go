@@ -0,0 +1,286 @@
+package tools
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+)
+
+func testCommunityRule(sequenceNumber int64, action armmanagednetworkfabric.CommunityActionTypes, members ...string) *armmanagednetworkfabric.IPCommunityRule {
+	rule := &armmanagednetworkfabric.IPCommunityRule{
+		Action:         to.Ptr(action),
+		SequenceNumber: to.Ptr(sequenceNumber),
+	}
+	for _, member := range members {
+		if wellKnown, ok := wellKnownCommunity(member); ok {
+			rule.WellKnownCommunities = append(rule.WellKnownCommunities, to.Ptr(wellKnown))
+			continue
+		}
+		rule.CommunityMembers = append(rule.CommunityMembers, to.Ptr(member))
+	}
+	return rule
+}
+
+func TestNormalizeCommunity(t *testing.T) {
+	tests := []struct {
+		community string
+		want      string
+		ok        bool
+	}{
+		{"65001:100", "65001:100", true},
+		{" 65001:100 ", "65001:100", true},
+		{"4259905636", "65001:100", true},
+		{"no-export", "65535:65281", true},
+		{"NoAdvertise", "65535:65282", true},
+		{"graceful-shutdown", "65535:0", true},
+		{"internet", "", true},
+		{"65536:1", "", false},
+		{"65001:", "", false},
+		{"tag", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.community, func(t *testing.T) {
+			got, ok := normalizeCommunity(test.community)
+			if got != test.want || ok != test.ok {
+				t.Errorf("normalizeCommunity = %q, %v, want %q, %v", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestListMatches(t *testing.T) {
+	permit, deny := armmanagednetworkfabric.CommunityActionTypesPermit, armmanagednetworkfabric.CommunityActionTypesDeny
+	tests := []struct {
+		name    string
+		rules   []listRule
+		ok      bool
+		carried []string
+		want    bool
+	}{
+		{"every value carried", []listRule{{sequenceNumber: 10, action: permit, values: []string{"65001:100", "65001:200"}}}, true, []string{"65001:200", "65001:100", "65001:300"}, true},
+		{"value missing", []listRule{{sequenceNumber: 10, action: permit, values: []string{"65001:100", "65001:200"}}}, true, []string{"65001:100"}, false},
+		{"deny rule first", []listRule{{sequenceNumber: 10, action: deny, values: []string{"65001:100"}}, {sequenceNumber: 20, action: permit, values: []string{"65001:100"}}}, true, []string{"65001:100"}, false},
+		{"later rule", []listRule{{sequenceNumber: 10, action: deny, values: []string{"65001:200"}}, {sequenceNumber: 20, action: permit, values: []string{"65001:100"}}}, true, []string{"65001:100"}, true},
+		{"empty rule ignored", []listRule{{sequenceNumber: 10, action: permit}}, true, []string{}, false},
+		{"internet matches every route", []listRule{{sequenceNumber: 10, action: permit, internet: true}}, true, []string{}, true},
+		{"internet with values", []listRule{{sequenceNumber: 10, action: permit, internet: true, values: []string{"65001:100"}}}, true, []string{}, false},
+		{"not fetched", nil, false, []string{"65001:100"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, reason := listMatches(testIPCommunityID("c1"), IP_COMMUNITY_RESOURCE_TYPE, test.rules, test.ok, test.carried, "the communities of the route")
+			if got != test.want {
+				t.Errorf("listMatches = %v (%s), want %v", got, reason, test.want)
+			}
+		})
+	}
+}
+
+func TestPrefixListMatches(t *testing.T) {
+	ge, le, between := armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, armmanagednetworkfabric.ConditionLesserThanOrEqualTo, armmanagednetworkfabric.ConditionRange
+	denyFirst := testPrefixRule("Deny", "10.2.3.0/24", "", "")
+	denyFirst.SequenceNumber = to.Ptr(int64(5))
+
+	tests := []struct {
+		name  string
+		rules []*armmanagednetworkfabric.IPPrefixRule
+		route string
+		want  bool
+	}{
+		{"exact", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", "", "")}, "10.2.0.0/16", true},
+		{"exact more specific", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", "", "")}, "10.2.3.0/24", false},
+		{"ge", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", ge, "24")}, "10.2.3.0/24", true},
+		{"ge too short", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", ge, "24")}, "10.2.0.0/20", false},
+		{"le", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", le, "20")}, "10.2.16.0/20", true},
+		{"le too long", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", le, "20")}, "10.2.3.0/24", false},
+		{"range", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", between, "24-28")}, "10.2.3.0/26", true},
+		{"past range", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", between, "24-28")}, "10.2.3.0/30", false},
+		{"outside", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", ge, "16")}, "10.3.0.0/24", false},
+		{"lower sequence number deny", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", ge, "24"), denyFirst}, "10.2.3.0/24", false},
+		{"lower sequence number deny elsewhere", []*armmanagednetworkfabric.IPPrefixRule{testPrefixRule("Permit", "10.2.0.0/16", ge, "24"), denyFirst}, "10.2.4.0/24", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := testRoutePolicyModel(map[string][]*armmanagednetworkfabric.IPPrefixRule{"p1": test.rules})
+			got, reason := model.prefixListMatches(strings.ToLower(testIPPrefixID("p1")), netip.MustParsePrefix(test.route))
+			if got != test.want {
+				t.Errorf("prefixListMatches = %v (%s), want %v", got, reason, test.want)
+			}
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	permit, deny, next := armmanagednetworkfabric.RoutePolicyActionTypePermit, armmanagednetworkfabric.RoutePolicyActionTypeDeny, armmanagednetworkfabric.RoutePolicyActionTypeContinue
+	and := armmanagednetworkfabric.RoutePolicyConditionTypeAnd
+
+	// tag adds 65001:100 to the routes within 10.2.0.0/16 and continues, then the tagged routes are
+	// permitted with local preference 200 and no-export removed
+	tag := testStatement(10, next, testPrefixCondition("internal"))
+	tag.Action.IPCommunityProperties = &armmanagednetworkfabric.ActionIPCommunityProperties{
+		Add: &armmanagednetworkfabric.IPCommunityIDList{IPCommunityIDs: []*string{to.Ptr(testIPCommunityID("tag"))}},
+	}
+	tagged := testStatement(20, permit, testCommunityCondition(and, "", "tag"))
+	tagged.Action.LocalPreference = to.Ptr(int64(200))
+	tagged.Action.IPCommunityProperties = &armmanagednetworkfabric.ActionIPCommunityProperties{
+		Delete: &armmanagednetworkfabric.IPCommunityIDList{IPCommunityIDs: []*string{to.Ptr(testIPCommunityID("no-export"))}},
+	}
+	blocked := testStatement(5, deny, testPrefixCondition("blocked"))
+
+	model := testRoutePolicyModel(map[string][]*armmanagednetworkfabric.IPPrefixRule{
+		"internal": {testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "16")},
+		"blocked":  {testPrefixRule("Permit", "10.2.99.0/24", "", "")},
+	}, tagged, tag, blocked)
+	model.communities[strings.ToLower(testIPCommunityID("tag"))] = &armmanagednetworkfabric.IPCommunityProperties{
+		IPCommunityRules: []*armmanagednetworkfabric.IPCommunityRule{testCommunityRule(10, "Permit", "65001:100")},
+	}
+	model.communities[strings.ToLower(testIPCommunityID("no-export"))] = &armmanagednetworkfabric.IPCommunityProperties{
+		IPCommunityRules: []*armmanagednetworkfabric.IPCommunityRule{testCommunityRule(10, "Permit", "no-export")},
+	}
+
+	tests := []struct {
+		name             string
+		route            Route
+		action           string
+		matchedStatement int64
+		trace            []int64
+		communities      []string
+		localPreference  int64
+	}{
+		{
+			name:             "continue tags the route for a later statement",
+			route:            Route{Prefix: "10.2.3.0/24", Communities: []string{"65535:65281", "65002:1"}},
+			action:           "Permit",
+			matchedStatement: 20,
+			trace:            []int64{5, 10, 20},
+			communities:      []string{"65002:1", "65001:100"},
+			localPreference:  200,
+		},
+		{
+			name:             "already tagged outside the continue statement",
+			route:            Route{Prefix: "192.168.0.0/24", Communities: []string{"65001:100"}},
+			action:           "Permit",
+			matchedStatement: 20,
+			trace:            []int64{5, 10, 20},
+			communities:      []string{"65001:100"},
+			localPreference:  200,
+		},
+		{
+			name:             "denied first",
+			route:            Route{Prefix: "10.2.99.0/24"},
+			action:           "Deny",
+			matchedStatement: 5,
+			trace:            []int64{5},
+		},
+		{
+			name:   "implicit deny",
+			route:  Route{Prefix: "192.168.0.0/24"},
+			action: "Deny",
+			trace:  []int64{5, 10, 20},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			simulation := model.simulate(test.route)
+
+			if simulation.Action != test.action {
+				t.Errorf("action = %s, want %s", simulation.Action, test.action)
+			}
+			if test.matchedStatement == 0 && simulation.MatchedStatement != nil {
+				t.Errorf("matched statement = %d, want the implicit deny", *simulation.MatchedStatement)
+			}
+			if test.matchedStatement != 0 && (simulation.MatchedStatement == nil || *simulation.MatchedStatement != test.matchedStatement) {
+				t.Errorf("matched statement = %v, want %d", simulation.MatchedStatement, test.matchedStatement)
+			}
+
+			trace := []int64{}
+			for _, evaluation := range simulation.Trace {
+				trace = append(trace, evaluation.SequenceNumber)
+			}
+			if !slices.Equal(trace, test.trace) {
+				t.Errorf("trace = %v, want %v", trace, test.trace)
+			}
+
+			if test.action != "Permit" {
+				if simulation.Result != nil {
+					t.Errorf("result = %v, want none for a denied route", simulation.Result)
+				}
+				return
+			}
+			if simulation.Result == nil {
+				t.Fatalf("result missing for a permitted route")
+			}
+			if !slices.Equal(simulation.Result.Communities, test.communities) {
+				t.Errorf("communities = %v, want %v", simulation.Result.Communities, test.communities)
+			}
+			if simulation.Result.LocalPreference == nil || *simulation.Result.LocalPreference != test.localPreference {
+				t.Errorf("local preference = %v, want %d", simulation.Result.LocalPreference, test.localPreference)
+			}
+			if !slices.Equal(simulation.Route.Communities, test.route.Communities) {
+				t.Errorf("the given route was modified: %v", simulation.Route.Communities)
+			}
+		})
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            map[string]any
+		localPreference *int64
+		wantErr         string
+	}{
+		{name: "no local preference", args: map[string]any{"prefix": "10.0.0.0/24"}},
+		{name: "whole local preference", args: map[string]any{"prefix": "10.0.0.0/24", "localPreference": float64(100)}, localPreference: to.Ptr(int64(100))},
+		{name: "fractional local preference", args: map[string]any{"prefix": "10.0.0.0/24", "localPreference": 100.7}, wantErr: "invalid local preference '100.7'"},
+		{name: "negative local preference", args: map[string]any{"prefix": "10.0.0.0/24", "localPreference": float64(-1)}, wantErr: "invalid local preference '-1'"},
+		{name: "local preference beyond 32 bits", args: map[string]any{"prefix": "10.0.0.0/24", "localPreference": float64(1 << 32)}, wantErr: "invalid local preference '4.294967296e+09'"},
+		{name: "local preference as a string", args: map[string]any{"prefix": "10.0.0.0/24", "localPreference": "100"}, wantErr: "invalid local preference '100'"},
+		{name: "prefix missing", args: map[string]any{}, wantErr: "route prefix missing"},
+		{name: "invalid community", args: map[string]any{"prefix": "10.0.0.0/24", "communities": []any{"tag"}}, wantErr: "invalid community 'tag'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route, err := parseRoute(test.args)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("error = %v, want one with %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRoute failed: %v", err)
+			}
+			if (route.LocalPreference == nil) != (test.localPreference == nil) || (route.LocalPreference != nil && *route.LocalPreference != *test.localPreference) {
+				t.Errorf("local preference = %v, want %v", route.LocalPreference, test.localPreference)
+			}
+		})
+	}
+}
+
+func TestSimulateDuplicateSequenceNumber(t *testing.T) {
+	permit, deny := armmanagednetworkfabric.RoutePolicyActionTypePermit, armmanagednetworkfabric.RoutePolicyActionTypeDeny
+	model := testRoutePolicyModel(map[string][]*armmanagednetworkfabric.IPPrefixRule{
+		"internal": {testPrefixRule("Permit", "10.2.0.0/16", armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, "16")},
+	}, testStatement(10, deny, testPrefixCondition("internal")), testStatement(10, permit, testPrefixCondition("internal")))
+
+	simulation := model.simulate(Route{Prefix: "10.2.3.0/24"})
+	if simulation.Action != string(permit) {
+		t.Errorf("action = %s, want the one of the statement listed last", simulation.Action)
+	}
+	if len(simulation.Trace) != 1 {
+		t.Errorf("trace = %v, want only the statement listed last", simulation.Trace)
+	}
+	if len(simulation.Warnings) != 1 || !strings.Contains(simulation.Warnings[0], "sequence number 10 is used by more than one statement") {
+		t.Errorf("warnings = %v, want one about sequence number 10", simulation.Warnings)
+	}
+}
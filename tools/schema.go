@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Properties that ARM reports but never accepts as input.
+var readOnlyProperties = []string{
+	"provisioningState",
+	"administrativeState",
+	"configurationState",
+}
+
+// SDK property structs whose JSON names make up the key table used for schema generation.
+var schemaTypes = []any{
+	armmanagednetworkfabric.IPPrefixProperties{},
+	armmanagednetworkfabric.IPPrefixPatchProperties{},
+	armmanagednetworkfabric.IPCommunityProperties{},
+	armmanagednetworkfabric.IPCommunityPatchableProperties{},
+	armmanagednetworkfabric.IPExtendedCommunityProperties{},
+	armmanagednetworkfabric.IPExtendedCommunityPatchProperties{},
+	armmanagednetworkfabric.RoutePolicyProperties{},
+	armmanagednetworkfabric.RoutePolicyPatchableProperties{},
+	armmanagednetworkfabric.L2IsolationDomainProperties{},
+	armmanagednetworkfabric.L2IsolationDomainPatchProperties{},
+	armmanagednetworkfabric.L3IsolationDomainProperties{},
+	armmanagednetworkfabric.L3IsolationDomainPatchProperties{},
+	armmanagednetworkfabric.InternalNetworkProperties{},
+	armmanagednetworkfabric.InternalNetworkPatchProperties{},
+	armmanagednetworkfabric.ExternalNetworkProperties{},
+	armmanagednetworkfabric.ExternalNetworkPatchProperties{},
+}
+
+// Enum types used by the property structs, mapped to their possible values.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(armmanagednetworkfabric.Action("")):                       enumValues(armmanagednetworkfabric.PossibleActionValues()),
+	reflect.TypeOf(armmanagednetworkfabric.AddressFamilyType("")):            enumValues(armmanagednetworkfabric.PossibleAddressFamilyTypeValues()),
+	reflect.TypeOf(armmanagednetworkfabric.AllowASOverride("")):              enumValues(armmanagednetworkfabric.PossibleAllowASOverrideValues()),
+	reflect.TypeOf(armmanagednetworkfabric.BooleanEnumProperty("")):          enumValues(armmanagednetworkfabric.PossibleBooleanEnumPropertyValues()),
+	reflect.TypeOf(armmanagednetworkfabric.CommunityActionTypes("")):         enumValues(armmanagednetworkfabric.PossibleCommunityActionTypesValues()),
+	reflect.TypeOf(armmanagednetworkfabric.Condition("")):                    enumValues(armmanagednetworkfabric.PossibleConditionValues()),
+	reflect.TypeOf(armmanagednetworkfabric.Extension("")):                    enumValues(armmanagednetworkfabric.PossibleExtensionValues()),
+	reflect.TypeOf(armmanagednetworkfabric.IsMonitoringEnabled("")):          enumValues(armmanagednetworkfabric.PossibleIsMonitoringEnabledValues()),
+	reflect.TypeOf(armmanagednetworkfabric.PeeringOption("")):                enumValues(armmanagednetworkfabric.PossiblePeeringOptionValues()),
+	reflect.TypeOf(armmanagednetworkfabric.RedistributeConnectedSubnets("")): enumValues(armmanagednetworkfabric.PossibleRedistributeConnectedSubnetsValues()),
+	reflect.TypeOf(armmanagednetworkfabric.RedistributeStaticRoutes("")):     enumValues(armmanagednetworkfabric.PossibleRedistributeStaticRoutesValues()),
+	reflect.TypeOf(armmanagednetworkfabric.RoutePolicyActionType("")):        enumValues(armmanagednetworkfabric.PossibleRoutePolicyActionTypeValues()),
+	reflect.TypeOf(armmanagednetworkfabric.RoutePolicyConditionType("")):     enumValues(armmanagednetworkfabric.PossibleRoutePolicyConditionTypeValues()),
+	reflect.TypeOf(armmanagednetworkfabric.WellKnownCommunities("")):         enumValues(armmanagednetworkfabric.PossibleWellKnownCommunitiesValues()),
+}
+
+var (
+	schemaKeysOnce sync.Once
+	schemaKeys     map[string]string
+)
+
+// propertiesParameter declares the "properties" argument as a JSON object whose schema is generated
+// from the SDK property struct T.
+func propertiesParameter[T any](description string) mcp.ToolOption {
+	return mcp.WithObject("properties",
+		mcp.Required(),
+		mcp.Description(description),
+		mcp.Properties(propertiesSchema[T]()),
+	)
+}
+
+// propertiesSchema returns the JSON Schema properties of the SDK struct T, keyed by their ARM names.
+func propertiesSchema[T any]() map[string]any {
+	var zero T
+
+	reflector := jsonschema.Reflector{
+		DoNotReference:             true,
+		Anonymous:                  true,
+		AllowAdditionalProperties:  true,
+		RequiredFromJSONSchemaTags: true,
+		KeyNamer:                   schemaKeyName,
+		Mapper: func(t reflect.Type) *jsonschema.Schema {
+			values, ok := schemaEnums[t]
+			if !ok {
+				return nil
+			}
+			schema := &jsonschema.Schema{Type: "string"}
+			for _, value := range values {
+				schema.Enum = append(schema.Enum, value)
+			}
+			return schema
+		},
+	}
+	schema := reflector.Reflect(zero)
+
+	schemaJson, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+
+	var generated struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(schemaJson, &generated); err != nil {
+		return nil
+	}
+
+	for _, key := range readOnlyProperties {
+		delete(generated.Properties, key)
+	}
+
+	return generated.Properties
+}
+
+// parseProperties unmarshals the "properties" argument into target. Both a JSON object and a
+// JSON-encoded string are accepted.
+func parseProperties(args map[string]any, target any) error {
+	return parseObjectArgument(args, "properties", target)
+}
+
+// parseObjectArgument unmarshals the object argument key into target, like parseProperties.
+func parseObjectArgument(args map[string]any, key string, target any) error {
+	var argumentJson []byte
+
+	switch argument := args[key].(type) {
+	case string:
+		if argument == "" {
+			return fmt.Errorf("%s missing", key)
+		}
+		argumentJson = []byte(argument)
+	case map[string]any:
+		var err error
+		argumentJson, err = json.Marshal(argument)
+		if err != nil {
+			return fmt.Errorf("error marshalling %s: %v", key, err)
+		}
+	default:
+		return fmt.Errorf("%s missing", key)
+	}
+
+	if err := json.Unmarshal(argumentJson, target); err != nil {
+		return fmt.Errorf("error unmarshalling %s: %v", key, err)
+	}
+
+	return nil
+}
+
+// schemaKeyName maps an SDK field name such as NetworkFabricID to its ARM name networkFabricId.
+func schemaKeyName(fieldName string) string {
+	schemaKeysOnce.Do(func() {
+		schemaKeys = make(map[string]string)
+		for _, schemaType := range schemaTypes {
+			value := reflect.New(reflect.TypeOf(schemaType)).Elem()
+			populateValue(value, 0)
+
+			valueJson, err := json.Marshal(value.Addr().Interface())
+			if err != nil {
+				continue
+			}
+			var decoded any
+			if err := json.Unmarshal(valueJson, &decoded); err != nil {
+				continue
+			}
+			collectKeys(decoded, schemaKeys)
+		}
+	})
+
+	if key, ok := schemaKeys[strings.ToLower(fieldName)]; ok {
+		return key
+	}
+	return strings.ToLower(fieldName[:1]) + fieldName[1:]
+}
+
+// populateValue fills every field so the SDK marshaller emits all of its keys.
+func populateValue(value reflect.Value, depth int) {
+	if depth > 10 {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		value.Set(reflect.New(value.Type().Elem()))
+		populateValue(value.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				populateValue(value.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		populateValue(value.Index(0), depth+1)
+	case reflect.String:
+		value.SetString("x")
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Bool:
+		value.SetBool(true)
+	}
+}
+
+func collectKeys(value any, keys map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			keys[strings.ToLower(key)] = key
+			collectKeys(child, keys)
+		}
+	case []any:
+		for _, child := range v {
+			collectKeys(child, keys)
+		}
+	}
+}
+
+func enumValues[T ~string](values []T) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, string(value))
+	}
+	return result
+}
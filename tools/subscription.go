@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func ListSubscriptions(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return listSubscriptions(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		subscriptions, err := getSubscriptions(ctx, clientRetriever, cred)
+		if err != nil {
+			return armErrorResult("failed to list subscriptions", err)
+		}
+
+		resJson, err := json.Marshal(subscriptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %v", err)
+		}
+
+		subscriptionList := SubscriptionList{
+			Subscriptions: make([]SubscriptionSummary, 0, len(subscriptions)),
+		}
+		for _, subscription := range subscriptions {
+			summary := SubscriptionSummary{
+				SubscriptionID: stringValue(subscription.SubscriptionID),
+				DisplayName:    stringValue(subscription.DisplayName),
+				State:          stringValue(subscription.State),
+			}
+			if subscription.TenantID != nil {
+				summary.TenantID = *subscription.TenantID
+			}
+			subscriptionList.Subscriptions = append(subscriptionList.Subscriptions, summary)
+		}
+
+		return mcp.NewToolResultStructured(subscriptionList, string(resJson)), nil
+	}
+}
+
+func listSubscriptions() mcp.Tool {
+	return mcp.NewTool(
+		LIST_SUBSCRIPTIONS_TOOL_NAME,
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[SubscriptionList](),
+		mcp.WithDescription("List the Azure subscriptions the credential can access"),
+	)
+}
+
+func FindNexusResources(clientRetriever ServiceClientRetriever) (mcp.Tool, server.ToolHandlerFunc) {
+	return findNexusResources(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid arguments format")
+		}
+
+		name, _ := args["name"].(string)
+		resourceType, _ := args["resourceType"].(string)
+		if name == "" && resourceType == "" {
+			return nil, errors.New("name or resource type missing")
+		}
+		if resourceType != "" && !strings.Contains(resourceType, "/") {
+			resourceType = NETWORK_FABRIC_PROVIDER + "/" + resourceType
+		}
+		if resourceType != "" && !strings.HasPrefix(strings.ToLower(resourceType), strings.ToLower(NETWORK_FABRIC_PROVIDER)+"/") {
+			return nil, fmt.Errorf("resource type '%s' is not a %s resource type", resourceType, NETWORK_FABRIC_PROVIDER)
+		}
+
+		cred, err := clientRetriever.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error getting credentials: %v", err)
+		}
+
+		var subscriptionIds []string
+		if ids, ok := args["subscriptionIds"].([]any); ok {
+			for _, id := range ids {
+				if id, ok := id.(string); ok && id != "" {
+					subscriptionIds = append(subscriptionIds, id)
+				}
+			}
+		}
+		if len(subscriptionIds) == 0 {
+			subscriptions, err := getSubscriptions(ctx, clientRetriever, cred)
+			if err != nil {
+				return armErrorResult("failed to list subscriptions", err)
+			}
+			for _, subscription := range subscriptions {
+				subscriptionIds = append(subscriptionIds, stringValue(subscription.SubscriptionID))
+			}
+		}
+
+		// ARM filters on either the type or a name substring, so the name is always checked here too
+		var filter string
+		if resourceType != "" {
+			filter = fmt.Sprintf("resourceType eq '%s'", resourceType)
+		} else {
+			filter = fmt.Sprintf("substringof('%s', name)", strings.ReplaceAll(name, "'", "''"))
+		}
+
+		found := NexusResourceList{
+			Resources: make([]NexusResource, 0),
+		}
+		for _, subscriptionId := range subscriptionIds {
+			resources, err := findResources(ctx, clientRetriever, cred, subscriptionId, filter)
+			if err != nil {
+				if armErr, ok := decodeARMError(err); ok {
+					err = fmt.Errorf("%s: %s", armErr.Code, armErr.Message)
+				}
+				found.Errors = append(found.Errors, fmt.Sprintf("%s: %v", subscriptionId, err))
+				continue
+			}
+
+			for _, resource := range resources {
+				if !strings.HasPrefix(strings.ToLower(stringValue(resource.Type)), strings.ToLower(NETWORK_FABRIC_PROVIDER)+"/") {
+					continue
+				}
+				if !strings.Contains(strings.ToLower(stringValue(resource.Name)), strings.ToLower(name)) {
+					continue
+				}
+
+				nexusResource := NexusResource{
+					ID:             stringValue(resource.ID),
+					Name:           stringValue(resource.Name),
+					Type:           stringValue(resource.Type),
+					SubscriptionID: subscriptionId,
+				}
+				if resource.Location != nil {
+					nexusResource.Location = *resource.Location
+				}
+				if resourceID, err := arm.ParseResourceID(nexusResource.ID); err == nil {
+					nexusResource.ResourceGroup = resourceID.ResourceGroupName
+				}
+				found.Resources = append(found.Resources, nexusResource)
+			}
+		}
+
+		resultString := fmt.Sprintf("Found %d resource(s) in %d subscription(s):\n", len(found.Resources), len(subscriptionIds))
+		for _, resource := range found.Resources {
+			resultString += fmt.Sprintf("- %s (%s) in resource group '%s' of subscription '%s'\n", resource.Name, resource.Type, resource.ResourceGroup, resource.SubscriptionID)
+		}
+		for _, searchError := range found.Errors {
+			resultString += fmt.Sprintf("- Could not search subscription %s\n", searchError)
+		}
+
+		return mcp.NewToolResultStructured(found, resultString), nil
+	}
+}
+
+func findNexusResources() mcp.Tool {
+	return mcp.NewTool(
+		FIND_NEXUS_RESOURCES_TOOL_NAME,
+		mcp.WithString("name",
+			mcp.Description(FIND_NAME_DESCRIPTION),
+		),
+		mcp.WithString("resourceType",
+			mcp.Description(FIND_RESOURCE_TYPE_DESCRIPTION),
+		),
+		mcp.WithArray("subscriptionIds",
+			mcp.Description(FIND_SUBSCRIPTION_IDS_DESCRIPTION),
+			mcp.WithStringItems(),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[NexusResourceList](),
+		mcp.WithDescription("Find Managed Network Fabric resources by name or type across all accessible subscriptions, e.g. to locate the resource group of a fabric. Child resources such as internal networks are not listed; find their parent instead."),
+	)
+}
+
+func getSubscriptions(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential) ([]*armsubscriptions.Subscription, error) {
+	client, err := armsubscriptions.NewClient(cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriptions client: %v", err)
+	}
+
+	pager := client.NewListPager(nil)
+
+	subscriptions := make([]*armsubscriptions.Subscription, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, page.Value...)
+	}
+
+	return subscriptions, nil
+}
+
+func findResources(ctx context.Context, clientRetriever ServiceClientRetriever, cred azcore.TokenCredential, subscriptionId, filter string) ([]*armresources.GenericResourceExpanded, error) {
+	client, err := armresources.NewClient(subscriptionId, cred, clientRetriever.ClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resources client: %v", err)
+	}
+
+	pager := client.NewListPager(&armresources.ClientListOptions{Filter: &filter})
+
+	resources := make([]*armresources.GenericResourceExpanded, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Value...)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TRACER_NAME = "github.com/sachinDcoder/mcp_azure_nexus_go/tools"
+
+// TelemetryMiddleware logs every tool call and wraps it in a span. The spans are exported by
+// whichever tracer provider is registered with otel, and dropped when none is.
+func TelemetryMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := otel.Tracer(TRACER_NAME).Start(ctx, "tool "+request.Params.Name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attribute.String("mcp.tool.name", request.Params.Name)),
+			)
+			defer span.End()
+
+			start := time.Now()
+			slog.DebugContext(ctx, "tool call started", "tool", request.Params.Name, "arguments", sanitizeArguments(request.Params.Arguments))
+
+			result, err := next(ctx, request)
+
+			outcome, message := callOutcome(result, err)
+			span.SetAttributes(attribute.String("mcp.tool.outcome", outcome))
+
+			attrs := []any{"tool", request.Params.Name, "outcome", outcome, "duration", time.Since(start)}
+			if outcome == OUTCOME_SUCCESS {
+				slog.InfoContext(ctx, "tool call finished", attrs...)
+			} else {
+				span.SetStatus(codes.Error, message)
+				slog.WarnContext(ctx, "tool call failed", append(attrs, "error", message)...)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// TelemetryPolicy is a pipeline policy that logs every ARM request and records it as a child span
+// of the tool call that made it.
+type TelemetryPolicy struct{}
+
+func (TelemetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := otel.Tracer(TRACER_NAME).Start(raw.Context(), "ARM "+raw.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", raw.Method),
+			attribute.String("url.full", raw.URL.String()),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := req.Next()
+	duration := time.Since(start)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.WarnContext(ctx, "ARM request failed", "method", raw.Method, "url", raw.URL.String(), "duration", duration, "error", err)
+		return resp, err
+	}
+
+	correlationID := resp.Header.Get("x-ms-correlation-request-id")
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		attribute.String("azure.correlation_request_id", correlationID),
+	)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	slog.DebugContext(ctx, "ARM request", "method", raw.Method, "url", raw.URL.String(), "status", resp.StatusCode, "duration", duration, "correlationId", correlationID)
+
+	return resp, err
+}
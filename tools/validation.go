@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	IP_VERSION_IPV4 = "ipv4"
+	IP_VERSION_IPV6 = "ipv6"
+)
+
+// validationErrorResult lists every violation found in the arguments, so they can all be fixed
+// before the next call instead of one failed long running operation at a time.
+func validationErrorResult(subject string, violations []string) *mcp.CallToolResult {
+	result := fmt.Sprintf("Invalid %s, nothing was sent to Azure:\n", subject)
+	for _, violation := range violations {
+		result += fmt.Sprintf("- %s\n", violation)
+	}
+	return mcp.NewToolResultError(result)
+}
+
+// ipVersions reads the IP versions an IP prefix is declared for, given as an array or as a JSON
+// string array. No versions allow both.
+func ipVersions(args map[string]any) ([]string, error) {
+	var values []any
+	switch types := args["types"].(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		values = types
+	case string:
+		if err := json.Unmarshal([]byte(types), &values); err != nil {
+			return nil, fmt.Errorf("invalid types '%s', expected a JSON string array e.g. [\"ipv6\"]: %v", types, err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid types %v, expected an array of %s or %s", types, IP_VERSION_IPV4, IP_VERSION_IPV6)
+	}
+
+	versions := make([]string, 0, len(values))
+	for _, value := range values {
+		version, _ := value.(string)
+		version = strings.ToLower(version)
+		if version != IP_VERSION_IPV4 && version != IP_VERSION_IPV6 {
+			return nil, fmt.Errorf("invalid IP version '%v', expected %s or %s", value, IP_VERSION_IPV4, IP_VERSION_IPV6)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// validateIPPrefixRules checks the rules the way the devices will: every network prefix is a CIDR
+// in one of the declared IP versions, the subnet mask length fits the prefix length and the
+// condition, and sequence numbers are unique.
+func validateIPPrefixRules(rules []*armmanagednetworkfabric.IPPrefixRule, versions []string) []string {
+	violations := []string{}
+	sequenceNumbers := map[int64]int{}
+
+	for i, rule := range rules {
+		if rule == nil {
+			violations = append(violations, fmt.Sprintf("ipPrefixRules[%d] is empty", i))
+			continue
+		}
+		ruleName, ruleViolations := validateRule("ipPrefixRules", i, rule.SequenceNumber, rule.Action, sequenceNumbers)
+		violations = append(violations, ruleViolations...)
+
+		if rule.NetworkPrefix == nil || *rule.NetworkPrefix == "" {
+			violations = append(violations, fmt.Sprintf("%s: networkPrefix missing", ruleName))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(*rule.NetworkPrefix)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: networkPrefix '%s' is not a valid CIDR", ruleName, *rule.NetworkPrefix))
+			continue
+		}
+		if prefix.Masked() != prefix {
+			violations = append(violations, fmt.Sprintf("%s: networkPrefix '%s' has host bits set, did you mean '%s'?", ruleName, *rule.NetworkPrefix, prefix.Masked()))
+		}
+
+		version := IP_VERSION_IPV4
+		if prefix.Addr().Is6() {
+			version = IP_VERSION_IPV6
+		}
+		if len(versions) > 0 && !slices.Contains(versions, version) {
+			violations = append(violations, fmt.Sprintf("%s: networkPrefix '%s' is %s but the IP prefix is declared for %s", ruleName, *rule.NetworkPrefix, version, strings.Join(versions, ", ")))
+		}
+
+		violations = append(violations, validateSubnetMaskLength(ruleName, prefix, rule.Condition, rule.SubnetMaskLength)...)
+	}
+
+	return violations
+}
+
+// validateRule checks the sequence number and action that every prefix and community rule has. It
+// returns the name of the rule to report its other violations with.
+func validateRule(list string, i int, sequenceNumber *int64, action *armmanagednetworkfabric.CommunityActionTypes, sequenceNumbers map[int64]int) (string, []string) {
+	violations := []string{}
+	ruleName := fmt.Sprintf("%s[%d]", list, i)
+
+	if sequenceNumber == nil {
+		violations = append(violations, fmt.Sprintf("%s: sequenceNumber missing", ruleName))
+	} else {
+		ruleName = fmt.Sprintf("%s (sequenceNumber %d)", ruleName, *sequenceNumber)
+		if first, ok := sequenceNumbers[*sequenceNumber]; ok {
+			violations = append(violations, fmt.Sprintf("%s: sequenceNumber is already used by %s[%d]", ruleName, list, first))
+		} else {
+			sequenceNumbers[*sequenceNumber] = i
+		}
+	}
+
+	if action == nil {
+		violations = append(violations, fmt.Sprintf("%s: action missing", ruleName))
+	} else if !slices.Contains(armmanagednetworkfabric.PossibleCommunityActionTypesValues(), *action) {
+		violations = append(violations, fmt.Sprintf("%s: action '%s' is not one of %s", ruleName, *action, strings.Join(enumValues(armmanagednetworkfabric.PossibleCommunityActionTypesValues()), ", ")))
+	}
+
+	return ruleName, violations
+}
+
+// validateSubnetMaskLength checks that the mask length is within the prefix length and the maximum
+// of the address family. A Range condition takes a length range such as 24-28.
+func validateSubnetMaskLength(ruleName string, prefix netip.Prefix, condition *armmanagednetworkfabric.Condition, subnetMaskLength *string) []string {
+	hasLength := subnetMaskLength != nil && *subnetMaskLength != ""
+	if condition == nil {
+		if hasLength {
+			return []string{fmt.Sprintf("%s: subnetMaskLength '%s' needs a condition", ruleName, *subnetMaskLength)}
+		}
+		return nil
+	}
+	if !slices.Contains(armmanagednetworkfabric.PossibleConditionValues(), *condition) {
+		return []string{fmt.Sprintf("%s: condition '%s' is not one of %s", ruleName, *condition, strings.Join(enumValues(armmanagednetworkfabric.PossibleConditionValues()), ", "))}
+	}
+	if !hasLength {
+		// EqualTo without a length matches the prefix length itself
+		if *condition == armmanagednetworkfabric.ConditionEqualTo {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: condition %s needs a subnetMaskLength", ruleName, *condition)}
+	}
+
+	maxLength := prefix.Addr().BitLen()
+	inBounds := func(length int) bool {
+		return length >= prefix.Bits() && length <= maxLength
+	}
+	bounds := fmt.Sprintf("between the prefix length %d and %d", prefix.Bits(), maxLength)
+
+	if *condition == armmanagednetworkfabric.ConditionRange {
+		low, high, ok := strings.Cut(*subnetMaskLength, "-")
+		lowLength, lowErr := strconv.Atoi(strings.TrimSpace(low))
+		highLength, highErr := strconv.Atoi(strings.TrimSpace(high))
+		if !ok || lowErr != nil || highErr != nil {
+			return []string{fmt.Sprintf("%s: condition Range needs a subnetMaskLength range such as %d-%d, got '%s'", ruleName, prefix.Bits(), maxLength, *subnetMaskLength)}
+		}
+		if !inBounds(lowLength) || !inBounds(highLength) || lowLength > highLength {
+			return []string{fmt.Sprintf("%s: subnetMaskLength range '%s' must be ascending and %s", ruleName, *subnetMaskLength, bounds)}
+		}
+		return nil
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(*subnetMaskLength))
+	if err != nil {
+		return []string{fmt.Sprintf("%s: subnetMaskLength '%s' is not a number", ruleName, *subnetMaskLength)}
+	}
+	if !inBounds(length) {
+		return []string{fmt.Sprintf("%s: subnetMaskLength %d with condition %s must be %s", ruleName, length, *condition, bounds)}
+	}
+	return nil
+}
+
+// validateIPCommunityRules checks that community members are standard communities, either ASN:NN or
+// a plain 32-bit number, and that well-known communities are given by name in their own list.
+func validateIPCommunityRules(rules []*armmanagednetworkfabric.IPCommunityRule) []string {
+	violations := []string{}
+	sequenceNumbers := map[int64]int{}
+
+	for i, rule := range rules {
+		if rule == nil {
+			violations = append(violations, fmt.Sprintf("ipCommunityRules[%d] is empty", i))
+			continue
+		}
+		ruleName, ruleViolations := validateRule("ipCommunityRules", i, rule.SequenceNumber, rule.Action, sequenceNumbers)
+		violations = append(violations, ruleViolations...)
+
+		if len(rule.CommunityMembers) == 0 && len(rule.WellKnownCommunities) == 0 {
+			violations = append(violations, fmt.Sprintf("%s: communityMembers missing", ruleName))
+		}
+
+		for j, member := range rule.CommunityMembers {
+			if member == nil {
+				violations = append(violations, fmt.Sprintf("%s: communityMembers[%d] is empty", ruleName, j))
+				continue
+			}
+			if wellKnown, ok := wellKnownCommunity(*member); ok {
+				violations = append(violations, fmt.Sprintf("%s: communityMembers[%d] '%s' is a well-known community, list it as '%s' in wellKnownCommunities instead", ruleName, j, *member, wellKnown))
+				continue
+			}
+			if !validCommunity(*member) {
+				violations = append(violations, fmt.Sprintf("%s: communityMembers[%d] '%s' is not a community, expected ASN:NN with both parts in 0-65535, e.g. 65001:100, or a 32-bit number", ruleName, j, *member))
+			}
+		}
+
+		for j, wellKnown := range rule.WellKnownCommunities {
+			if wellKnown == nil || !slices.Contains(armmanagednetworkfabric.PossibleWellKnownCommunitiesValues(), *wellKnown) {
+				value := ""
+				if wellKnown != nil {
+					value = string(*wellKnown)
+				}
+				violations = append(violations, fmt.Sprintf("%s: wellKnownCommunities[%d] '%s' is not one of %s", ruleName, j, value, strings.Join(enumValues(armmanagednetworkfabric.PossibleWellKnownCommunitiesValues()), ", ")))
+			}
+		}
+	}
+
+	return violations
+}
+
+// validateIPExtCommunityRules checks that route targets use one of the formats ARM accepts:
+// ASN:NN with a 2 or 4-byte ASN, ASN.ASN:NN or IPv4:NN.
+func validateIPExtCommunityRules(rules []*armmanagednetworkfabric.IPExtendedCommunityRule) []string {
+	violations := []string{}
+	sequenceNumbers := map[int64]int{}
+
+	for i, rule := range rules {
+		if rule == nil {
+			violations = append(violations, fmt.Sprintf("ipExtendedCommunityRules[%d] is empty", i))
+			continue
+		}
+		ruleName, ruleViolations := validateRule("ipExtendedCommunityRules", i, rule.SequenceNumber, rule.Action, sequenceNumbers)
+		violations = append(violations, ruleViolations...)
+
+		if len(rule.RouteTargets) == 0 {
+			violations = append(violations, fmt.Sprintf("%s: routeTargets missing", ruleName))
+		}
+		for j, routeTarget := range rule.RouteTargets {
+			if routeTarget == nil || !validRouteTarget(*routeTarget) {
+				value := ""
+				if routeTarget != nil {
+					value = *routeTarget
+				}
+				violations = append(violations, fmt.Sprintf("%s: routeTargets[%d] '%s' is not a route target, expected ASN:NN e.g. 4294967294:50, ASN.ASN:NN e.g. 65533.65333:40 or IP:NN e.g. 10.10.10.10:65535, where NN is in 0-65535", ruleName, j, value))
+			}
+		}
+	}
+
+	return violations
+}
+
+func validCommunity(member string) bool {
+	asn, value, ok := strings.Cut(member, ":")
+	if !ok {
+		_, err := strconv.ParseUint(member, 10, 32)
+		return err == nil
+	}
+	return validUint16(asn) && validUint16(value)
+}
+
+func validRouteTarget(routeTarget string) bool {
+	// the administrator may be an IPv4 address, which has no colons, so split at the last one
+	index := strings.LastIndex(routeTarget, ":")
+	if index < 0 {
+		return false
+	}
+	administrator, value := routeTarget[:index], routeTarget[index+1:]
+	if !validUint16(value) {
+		return false
+	}
+
+	if high, low, ok := strings.Cut(administrator, "."); ok && !strings.Contains(low, ".") {
+		return validUint16(high) && validUint16(low)
+	}
+	if addr, err := netip.ParseAddr(administrator); err == nil {
+		return addr.Is4()
+	}
+	_, err := strconv.ParseUint(administrator, 10, 32)
+	return err == nil
+}
+
+func validUint16(value string) bool {
+	_, err := strconv.ParseUint(value, 10, 16)
+	return err == nil
+}
+
+// wellKnownCommunity returns the name of a well-known community written as a community member,
+// e.g. no-export or NO_EXPORT.
+func wellKnownCommunity(member string) (armmanagednetworkfabric.WellKnownCommunities, bool) {
+	normalized := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(member))
+	for _, wellKnown := range armmanagednetworkfabric.PossibleWellKnownCommunitiesValues() {
+		if strings.ToLower(string(wellKnown)) == normalized {
+			return wellKnown, true
+		}
+	}
+	switch normalized {
+	case "localas", "noexportsubconfed":
+		return armmanagednetworkfabric.WellKnownCommunitiesLocalAS, true
+	case "gracefulshutdown":
+		return armmanagednetworkfabric.WellKnownCommunitiesGShut, true
+	}
+	return "", false
+}
@@ -0,0 +1,322 @@
+package tools
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managednetworkfabric/armmanagednetworkfabric"
+)
+
+func TestIPVersions(t *testing.T) {
+	tests := []struct {
+		name  string
+		types any
+		want  []string
+		err   bool
+	}{
+		{name: "missing"},
+		{name: "array", types: []any{"IPv4", "ipv6"}, want: []string{IP_VERSION_IPV4, IP_VERSION_IPV6}},
+		{name: "empty array", types: []any{}, want: []string{}},
+		{name: "JSON string array", types: `["IPv6"]`, want: []string{IP_VERSION_IPV6}},
+		{name: "JSON string array of both", types: `["ipv4", "ipv6"]`, want: []string{IP_VERSION_IPV4, IP_VERSION_IPV6}},
+		{name: "plain string", types: "ipv6", err: true},
+		{name: "unknown version in array", types: []any{"ipv5"}, err: true},
+		{name: "unknown version in JSON string array", types: `["ipv4", "ipx"]`, err: true},
+		{name: "number in array", types: []any{4.0}, err: true},
+		{name: "object", types: map[string]any{"ipv4": true}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := map[string]any{}
+			if test.types != nil {
+				args["types"] = test.types
+			}
+			versions, err := ipVersions(args)
+			if test.err {
+				if err == nil {
+					t.Fatalf("ipVersions = %v, want an error", versions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ipVersions failed: %v", err)
+			}
+			if !slices.Equal(versions, test.want) || (versions == nil) != (test.want == nil) {
+				t.Errorf("ipVersions = %#v, want %#v", versions, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateSubnetMaskLength(t *testing.T) {
+	ge, le, eq, between := armmanagednetworkfabric.ConditionGreaterThanOrEqualTo, armmanagednetworkfabric.ConditionLesserThanOrEqualTo, armmanagednetworkfabric.ConditionEqualTo, armmanagednetworkfabric.ConditionRange
+	tests := []struct {
+		name      string
+		prefix    string
+		condition armmanagednetworkfabric.Condition
+		length    string
+		violation string
+	}{
+		{name: "no condition", prefix: "10.2.0.0/16"},
+		{name: "length without condition", prefix: "10.2.0.0/16", length: "24", violation: "needs a condition"},
+		{name: "unknown condition", prefix: "10.2.0.0/16", condition: "GreaterThan", length: "24", violation: "is not one of"},
+		{name: "equal to without length", prefix: "10.2.0.0/16", condition: eq},
+		{name: "ge without length", prefix: "10.2.0.0/16", condition: ge, violation: "needs a subnetMaskLength"},
+		{name: "ge", prefix: "10.2.0.0/16", condition: ge, length: "24"},
+		{name: "ge of the prefix length", prefix: "10.2.0.0/16", condition: ge, length: "16"},
+		{name: "ge of 32", prefix: "10.2.0.0/16", condition: ge, length: "32"},
+		{name: "ge beyond 32", prefix: "10.2.0.0/16", condition: ge, length: "33", violation: "between the prefix length 16 and 32"},
+		{name: "le beyond 32", prefix: "10.2.0.0/16", condition: le, length: "40", violation: "between the prefix length 16 and 32"},
+		{name: "le of 128", prefix: "fd00::/48", condition: le, length: "128"},
+		{name: "le beyond 128", prefix: "fd00::/48", condition: le, length: "129", violation: "between the prefix length 48 and 128"},
+		{name: "shorter than the prefix", prefix: "10.2.0.0/16", condition: ge, length: "8", violation: "between the prefix length 16 and 32"},
+		{name: "shorter than the IPv6 prefix", prefix: "fd00::/48", condition: eq, length: "32", violation: "between the prefix length 48 and 128"},
+		{name: "not a number", prefix: "10.2.0.0/16", condition: ge, length: "twenty", violation: "is not a number"},
+		{name: "range", prefix: "10.2.0.0/16", condition: between, length: "24-28"},
+		{name: "range with spaces", prefix: "10.2.0.0/16", condition: between, length: "24 - 28"},
+		{name: "range of one length", prefix: "10.2.0.0/16", condition: between, length: "24-24"},
+		{name: "descending range", prefix: "10.2.0.0/16", condition: between, length: "28-24", violation: "must be ascending"},
+		{name: "range beyond 32", prefix: "10.2.0.0/16", condition: between, length: "24-33", violation: "must be ascending and between the prefix length 16 and 32"},
+		{name: "range shorter than the prefix", prefix: "10.2.0.0/16", condition: between, length: "8-24", violation: "must be ascending and between the prefix length 16 and 32"},
+		{name: "range beyond 128", prefix: "fd00::/48", condition: between, length: "64-129", violation: "between the prefix length 48 and 128"},
+		{name: "single length for a range", prefix: "10.2.0.0/16", condition: between, length: "24", violation: "needs a subnetMaskLength range such as 16-32"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var condition *armmanagednetworkfabric.Condition
+			if test.condition != "" {
+				condition = to.Ptr(test.condition)
+			}
+			var length *string
+			if test.length != "" {
+				length = to.Ptr(test.length)
+			}
+
+			violations := validateSubnetMaskLength("rule", netip.MustParsePrefix(test.prefix), condition, length)
+			if test.violation == "" {
+				if len(violations) > 0 {
+					t.Errorf("violations = %v, want none", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || !strings.Contains(violations[0], test.violation) {
+				t.Errorf("violations = %v, want one with %q", violations, test.violation)
+			}
+		})
+	}
+}
+
+func TestValidateIPPrefixRules(t *testing.T) {
+	rule := func(sequenceNumber int64, prefix string) *armmanagednetworkfabric.IPPrefixRule {
+		return &armmanagednetworkfabric.IPPrefixRule{
+			Action:         to.Ptr(armmanagednetworkfabric.CommunityActionTypesPermit),
+			SequenceNumber: to.Ptr(sequenceNumber),
+			NetworkPrefix:  to.Ptr(prefix),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		rules      []*armmanagednetworkfabric.IPPrefixRule
+		versions   []string
+		violations []string
+	}{
+		{
+			name:  "valid",
+			rules: []*armmanagednetworkfabric.IPPrefixRule{rule(10, "10.2.0.0/16"), rule(20, "fd00::/48")},
+		},
+		{
+			name:       "duplicate sequence number",
+			rules:      []*armmanagednetworkfabric.IPPrefixRule{rule(10, "10.2.0.0/16"), rule(10, "10.3.0.0/16")},
+			violations: []string{"ipPrefixRules[1] (sequenceNumber 10): sequenceNumber is already used by ipPrefixRules[0]"},
+		},
+		{
+			name:       "host bits",
+			rules:      []*armmanagednetworkfabric.IPPrefixRule{rule(10, "10.2.3.4/16")},
+			violations: []string{"has host bits set, did you mean '10.2.0.0/16'?"},
+		},
+		{
+			name:       "not a CIDR",
+			rules:      []*armmanagednetworkfabric.IPPrefixRule{rule(10, "10.2.0.0")},
+			violations: []string{"networkPrefix '10.2.0.0' is not a valid CIDR"},
+		},
+		{
+			name:       "other IP version",
+			rules:      []*armmanagednetworkfabric.IPPrefixRule{rule(10, "10.2.0.0/16"), rule(20, "fd00::/48")},
+			versions:   []string{IP_VERSION_IPV6},
+			violations: []string{"'10.2.0.0/16' is ipv4 but the IP prefix is declared for ipv6"},
+		},
+		{
+			name:       "missing fields",
+			rules:      []*armmanagednetworkfabric.IPPrefixRule{{}, nil},
+			violations: []string{"ipPrefixRules[0]: sequenceNumber missing", "ipPrefixRules[0]: action missing", "ipPrefixRules[0]: networkPrefix missing", "ipPrefixRules[1] is empty"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations := validateIPPrefixRules(test.rules, test.versions)
+			if len(violations) != len(test.violations) {
+				t.Fatalf("violations = %v, want %d", violations, len(test.violations))
+			}
+			for i, want := range test.violations {
+				if !strings.Contains(violations[i], want) {
+					t.Errorf("violation %q, want one with %q", violations[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidCommunity(t *testing.T) {
+	tests := []struct {
+		member string
+		want   bool
+	}{
+		{"65001:100", true},
+		{"0:0", true},
+		{"65535:65535", true},
+		{"4294967295", true},
+		{"65536:100", false},
+		{"65001:65536", false},
+		{"4294967296", false},
+		{"65001:", false},
+		{":100", false},
+		{"65001:100:1", false},
+		{"-1:100", false},
+		{"no-export", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.member, func(t *testing.T) {
+			if got := validCommunity(test.member); got != test.want {
+				t.Errorf("validCommunity = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidRouteTarget(t *testing.T) {
+	tests := []struct {
+		routeTarget string
+		want        bool
+	}{
+		{"65001:100", true},
+		{"4294967294:50", true},
+		{"65533.65333:40", true},
+		{"10.10.10.10:65535", true},
+		{"4294967296:50", false},
+		{"65001:65536", false},
+		{"65536.1:40", false},
+		{"1.2.3:40", false},
+		{"10.10.10.256:1", false},
+		{"fd00::1:100", false},
+		{"65001", false},
+		{"65001:", false},
+		{":100", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.routeTarget, func(t *testing.T) {
+			if got := validRouteTarget(test.routeTarget); got != test.want {
+				t.Errorf("validRouteTarget = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWellKnownCommunity(t *testing.T) {
+	tests := []struct {
+		member string
+		want   armmanagednetworkfabric.WellKnownCommunities
+		ok     bool
+	}{
+		{"no-export", armmanagednetworkfabric.WellKnownCommunitiesNoExport, true},
+		{"NO_EXPORT", armmanagednetworkfabric.WellKnownCommunitiesNoExport, true},
+		{"NoAdvertise", armmanagednetworkfabric.WellKnownCommunitiesNoAdvertise, true},
+		{"internet", armmanagednetworkfabric.WellKnownCommunitiesInternet, true},
+		{"local-as", armmanagednetworkfabric.WellKnownCommunitiesLocalAS, true},
+		{"no-export-subconfed", armmanagednetworkfabric.WellKnownCommunitiesLocalAS, true},
+		{"graceful-shutdown", armmanagednetworkfabric.WellKnownCommunitiesGShut, true},
+		{"65001:100", "", false},
+		{"export", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.member, func(t *testing.T) {
+			got, ok := wellKnownCommunity(test.member)
+			if got != test.want || ok != test.ok {
+				t.Errorf("wellKnownCommunity = %q, %v, want %q, %v", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestValidateCommunityRules(t *testing.T) {
+	permit := to.Ptr(armmanagednetworkfabric.CommunityActionTypesPermit)
+	tests := []struct {
+		name       string
+		rules      []*armmanagednetworkfabric.IPCommunityRule
+		extRules   []*armmanagednetworkfabric.IPExtendedCommunityRule
+		violations []string
+	}{
+		{
+			name: "valid",
+			rules: []*armmanagednetworkfabric.IPCommunityRule{
+				{Action: permit, SequenceNumber: to.Ptr(int64(10)), CommunityMembers: []*string{to.Ptr("65001:100"), to.Ptr("4259905636")}},
+				{Action: permit, SequenceNumber: to.Ptr(int64(20)), WellKnownCommunities: []*armmanagednetworkfabric.WellKnownCommunities{to.Ptr(armmanagednetworkfabric.WellKnownCommunitiesNoExport)}},
+			},
+			extRules: []*armmanagednetworkfabric.IPExtendedCommunityRule{
+				{Action: permit, SequenceNumber: to.Ptr(int64(10)), RouteTargets: []*string{to.Ptr("65001:100"), to.Ptr("10.10.10.10:1")}},
+			},
+		},
+		{
+			name: "well-known community as a member",
+			rules: []*armmanagednetworkfabric.IPCommunityRule{
+				{Action: permit, SequenceNumber: to.Ptr(int64(10)), CommunityMembers: []*string{to.Ptr("no-export")}},
+			},
+			violations: []string{"ipCommunityRules[0] (sequenceNumber 10): communityMembers[0] 'no-export' is a well-known community, list it as 'NoExport' in wellKnownCommunities instead"},
+		},
+		{
+			name: "invalid formats",
+			rules: []*armmanagednetworkfabric.IPCommunityRule{
+				{Action: permit, SequenceNumber: to.Ptr(int64(10)), CommunityMembers: []*string{to.Ptr("65536:1")}},
+				{Action: to.Ptr(armmanagednetworkfabric.CommunityActionTypes("Allow")), SequenceNumber: to.Ptr(int64(10))},
+			},
+			extRules: []*armmanagednetworkfabric.IPExtendedCommunityRule{
+				{Action: permit, SequenceNumber: to.Ptr(int64(10)), RouteTargets: []*string{to.Ptr("65001")}},
+				{SequenceNumber: to.Ptr(int64(20))},
+			},
+			violations: []string{
+				"ipCommunityRules[0] (sequenceNumber 10): communityMembers[0] '65536:1' is not a community",
+				"ipCommunityRules[1] (sequenceNumber 10): sequenceNumber is already used by ipCommunityRules[0]",
+				"ipCommunityRules[1] (sequenceNumber 10): action 'Allow' is not one of",
+				"ipCommunityRules[1] (sequenceNumber 10): communityMembers missing",
+				"ipExtendedCommunityRules[0] (sequenceNumber 10): routeTargets[0] '65001' is not a route target",
+				"ipExtendedCommunityRules[1] (sequenceNumber 20): action missing",
+				"ipExtendedCommunityRules[1] (sequenceNumber 20): routeTargets missing",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations := append(validateIPCommunityRules(test.rules), validateIPExtCommunityRules(test.extRules)...)
+			if len(violations) != len(test.violations) {
+				t.Fatalf("violations = %v, want %d", violations, len(test.violations))
+			}
+			for i, want := range test.violations {
+				if !strings.HasPrefix(violations[i], want) {
+					t.Errorf("violation %q, want one starting with %q", violations[i], want)
+				}
+			}
+		})
+	}
+}
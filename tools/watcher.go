@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DEFAULT_WATCH_INTERVAL     = 30 * time.Second
+	DEFAULT_WATCH_MAX_INTERVAL = 5 * time.Minute
+)
+
+// WatcherConfig sets how often subscribed resources are polled. Durations use Go syntax, e.g. 30s.
+type WatcherConfig struct {
+	// Interval is the time between two polls of a resource. It defaults to 30s.
+	Interval string `json:"interval"`
+	// MaxInterval caps the backoff applied while polls fail or are throttled. It defaults to 5m.
+	MaxInterval string `json:"maxInterval"`
+}
+
+// resourceState is the part of a resource whose changes are notified.
+type resourceState struct {
+	ProvisioningState   string
+	AdministrativeState string
+	ConfigurationState  string
+	Deleted             bool
+}
+
+// Watcher polls the resources that clients subscribed to and sends notifications/resources/updated
+// to those clients when the provisioning, administrative or configuration state changes.
+type Watcher struct {
+	clientRetriever ServiceClientRetriever
+	interval        time.Duration
+	maxInterval     time.Duration
+
+	mu      sync.Mutex
+	server  *server.MCPServer
+	watches map[string]*resourceWatch
+}
+
+type resourceWatch struct {
+	sessions map[string]struct{}
+	cancel   context.CancelFunc
+}
+
+func NewWatcher(clientRetriever ServiceClientRetriever, config WatcherConfig) (*Watcher, error) {
+	watcher := &Watcher{
+		clientRetriever: clientRetriever,
+		interval:        DEFAULT_WATCH_INTERVAL,
+		maxInterval:     DEFAULT_WATCH_MAX_INTERVAL,
+		watches:         make(map[string]*resourceWatch),
+	}
+
+	var err error
+	if config.Interval != "" {
+		if watcher.interval, err = time.ParseDuration(config.Interval); err != nil || watcher.interval <= 0 {
+			return nil, fmt.Errorf("invalid watch interval '%s'", config.Interval)
+		}
+	}
+	if config.MaxInterval != "" {
+		if watcher.maxInterval, err = time.ParseDuration(config.MaxInterval); err != nil || watcher.maxInterval <= 0 {
+			return nil, fmt.Errorf("invalid watch max interval '%s'", config.MaxInterval)
+		}
+	}
+	if watcher.maxInterval < watcher.interval {
+		watcher.maxInterval = watcher.interval
+	}
+
+	return watcher, nil
+}
+
+// SetServer sets the server that notifications are sent through. Subscriptions are only possible
+// once the server exists, so this is called right after creating it.
+func (watcher *Watcher) SetServer(s *server.MCPServer) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.server = s
+}
+
+// Subscribe starts polling uri for the session of ctx. URIs that are not Network Fabric resources
+// are ignored.
+func (watcher *Watcher) Subscribe(ctx context.Context, uri string) {
+	resourceType, variables, ok := matchNexusURI(uri)
+	if !ok {
+		slog.DebugContext(ctx, "not watching resource", "uri", uri)
+		return
+	}
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	watch, ok := watcher.watches[uri]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		watch = &resourceWatch{
+			sessions: make(map[string]struct{}),
+			cancel:   cancel,
+		}
+		watcher.watches[uri] = watch
+		go watcher.poll(pollCtx, uri, resourceType, variables)
+	}
+	watch.sessions[sessionID(ctx)] = struct{}{}
+}
+
+// Unsubscribe stops sending updates of uri to the session of ctx.
+func (watcher *Watcher) Unsubscribe(ctx context.Context, uri string) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.remove(uri, sessionID(ctx))
+}
+
+// Forget drops the subscriptions of a session that has ended.
+func (watcher *Watcher) Forget(session server.ClientSession) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	for uri := range watcher.watches {
+		watcher.remove(uri, session.SessionID())
+	}
+}
+
+// Close stops polling every resource.
+func (watcher *Watcher) Close() {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	for uri, watch := range watcher.watches {
+		watch.cancel()
+		delete(watcher.watches, uri)
+	}
+}
+
+func (watcher *Watcher) remove(uri, session string) {
+	watch, ok := watcher.watches[uri]
+	if !ok {
+		return
+	}
+	delete(watch.sessions, session)
+	if len(watch.sessions) == 0 {
+		watch.cancel()
+		delete(watcher.watches, uri)
+	}
+}
+
+// poll gets the resource every interval until its watch is cancelled. Failed polls double the
+// interval up to the maximum; the first successful one goes back to the configured interval.
+func (watcher *Watcher) poll(ctx context.Context, uri string, resourceType nexusResourceType, variables map[string]string) {
+	last, err := watcher.state(ctx, resourceType, variables)
+	known := err == nil
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get watched resource", "uri", uri, "error", err)
+	}
+
+	interval := watcher.interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		current, err := watcher.state(ctx, resourceType, variables)
+		if err != nil {
+			interval = min(interval*2, watcher.maxInterval)
+			slog.WarnContext(ctx, "failed to get watched resource", "uri", uri, "error", err, "retryIn", interval)
+			continue
+		}
+		interval = watcher.interval
+
+		if known && current != last {
+			slog.InfoContext(ctx, "watched resource changed", "uri", uri, "from", last, "to", current)
+			watcher.notify(uri)
+		}
+		last, known = current, true
+	}
+}
+
+// state gets the states of a resource. A resource that no longer exists is reported as deleted
+// rather than as a failure, so its subscribers hear about it.
+func (watcher *Watcher) state(ctx context.Context, resourceType nexusResourceType, variables map[string]string) (resourceState, error) {
+	res, err := resourceType.fetch(ctx, watcher.clientRetriever, variables)
+	if err != nil {
+		if armErr, ok := decodeARMError(err); ok && armErr.StatusCode == http.StatusNotFound {
+			return resourceState{Deleted: true}, nil
+		}
+		return resourceState{}, err
+	}
+
+	summary, err := newResourceSummary(res)
+	if err != nil {
+		return resourceState{}, err
+	}
+
+	return resourceState{
+		ProvisioningState:   summary.ProvisioningState,
+		AdministrativeState: summary.AdministrativeState,
+		ConfigurationState:  summary.ConfigurationState,
+	}, nil
+}
+
+func (watcher *Watcher) notify(uri string) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	watch, ok := watcher.watches[uri]
+	if !ok || watcher.server == nil {
+		return
+	}
+
+	for session := range watch.sessions {
+		err := watcher.server.SendNotificationToSpecificClient(session, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if err != nil {
+			slog.Warn("failed to send resource update", "uri", uri, "session", session, "error", err)
+		}
+	}
+}